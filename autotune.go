@@ -0,0 +1,91 @@
+// Copyright (c) 2026 The Decred developers
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// defaultAutoTuneFilename is the name of the file in the home directory the
+// tuned intensities are saved in with --autotunesave.
+const defaultAutoTuneFilename = "autotune.json"
+
+var (
+	autoTuneFile = filepath.Join(minerHomeDir, defaultAutoTuneFilename)
+
+	// autoTuneMtx protects the auto-tune file since devices are tuned
+	// concurrently.
+	autoTuneMtx sync.Mutex
+)
+
+// autoTuneKey returns the key the intensity tuned for a device, algorithm and
+// target kernel run time is saved under.
+func autoTuneKey(deviceName string, algo PowAlgorithm, target time.Duration) string {
+	return fmt.Sprintf("%s/%s/%v", deviceName, algo.Name(), target)
+}
+
+// readTunedIntensities reads the saved intensities.  A missing file holds no
+// intensities.
+func readTunedIntensities() (map[string]int, error) {
+	intensities := make(map[string]int)
+	b, err := ioutil.ReadFile(autoTuneFile)
+	if os.IsNotExist(err) {
+		return intensities, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(b, &intensities)
+	if err != nil {
+		return nil, fmt.Errorf("Malformed auto-tune file %s: %v",
+			autoTuneFile, err)
+	}
+	return intensities, nil
+}
+
+// loadTunedIntensity returns the intensity saved under key if saving tuned
+// intensities is enabled and there is a valid one.
+func loadTunedIntensity(key string) (int, bool) {
+	if !cfg.AutoTuneSave {
+		return 0, false
+	}
+
+	autoTuneMtx.Lock()
+	defer autoTuneMtx.Unlock()
+
+	intensities, err := readTunedIntensities()
+	if err != nil {
+		minrLog.Warnf("Unable to load tuned intensities: %v", err)
+		return 0, false
+	}
+	intensity, ok := intensities[key]
+	if !ok || intensity < minIntensity || intensity > maxIntensity {
+		return 0, false
+	}
+	return intensity, true
+}
+
+// saveTunedIntensity saves the intensity under key, keeping the intensities
+// saved for other devices.
+func saveTunedIntensity(key string, intensity int) error {
+	autoTuneMtx.Lock()
+	defer autoTuneMtx.Unlock()
+
+	intensities, err := readTunedIntensities()
+	if err != nil {
+		return err
+	}
+	intensities[key] = intensity
+
+	b, err := json.MarshalIndent(intensities, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(autoTuneFile, append(b, '\n'), 0600)
+}
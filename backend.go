@@ -0,0 +1,159 @@
+// Copyright (c) 2026 The Decred developers
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Backend is implemented by every kind of mining hardware the miner knows how
+// to drive.  A backend enumerates its hardware and hands out one Worker for
+// each piece of it; the Miner only ever talks to those workers through a
+// Device.
+type Backend interface {
+	// Name returns the name the backend was registered under.
+	Name() string
+
+	// Workers enumerates the hardware available to the backend and
+	// returns a ready to use Worker for each.
+	Workers() ([]Worker, error)
+}
+
+// Worker is a single piece of mining hardware.  Workers only know how to
+// search a range of nonce0 values for a given midstate and final block; all
+// work management, candidate verification and statistics are handled by the
+// Device that owns them.
+type Worker interface {
+	// Name returns a human readable description of the hardware.
+	Name() string
+
+	// BatchSize returns the number of nonces the worker would like to
+	// search for each call to Search.  It may change after SetWork.
+	BatchSize() uint32
+
+	// SetWork loads the midstate of the first two header blocks and the
+	// final block, converted to uint32 words, into the worker along with
+	// the algorithm to hash them with.  The nonce0 word of lastBlock is
+	// ignored since it is filled in by Search.
+	SetWork(algo PowAlgorithm, midstate *[8]uint32, lastBlock *[16]uint32) error
+
+	// Search hashes count nonce0 values starting at start using the
+	// current work.  It returns the nonces whose final hash word is zero
+	// along with the number of hashes performed.
+	Search(start, count uint32) (candidates []uint32, hashes uint64, err error)
+
+	// Release frees any resources held by the worker.
+	Release()
+}
+
+// AsyncWorker is a Worker able to queue searches on its hardware, so that the
+// next search is already running while the results of the previous one are
+// being handled.  Devices use the queue instead of Search for these workers.
+type AsyncWorker interface {
+	Worker
+
+	// QueueSize returns the number of searches that can be queued at
+	// once.
+	QueueSize() int
+
+	// Enqueue queues a search of count nonce0 values starting at start
+	// using the current work and returns without waiting for it.  The
+	// work may be changed with SetWork right away.
+	Enqueue(start, count uint32) error
+
+	// Wait waits for the oldest queued search and returns its candidates
+	// along with the number of hashes performed.
+	//
+	// When Enqueue or Wait fail, every queued search is dropped.
+	Wait() (candidates []uint32, hashes uint64, err error)
+}
+
+// interruptibleWorker is a Worker whose searches may block for long, such as
+// the simulated stalls of the sim backend.  Devices interrupt them when they
+// are stopped so that stopping does not wait for the search.
+type interruptibleWorker interface {
+	Worker
+
+	// Interrupt makes a running search return early, and every later
+	// search return right away.  It may be called from any goroutine.
+	Interrupt()
+}
+
+// temporaryError is implemented by worker errors that may go away when the
+// search is retried later, such as the hardware running out of memory.
+type temporaryError interface {
+	Temporary() bool
+}
+
+// isTemporary returns whether err, or an error it wraps, is temporary.
+func isTemporary(err error) bool {
+	var t temporaryError
+	return errors.As(err, &t) && t.Temporary()
+}
+
+// unavailableError is returned by backends whose support is missing from the
+// system, such as the OpenCL backend without an OpenCL library.  The miner
+// falls back to the backend set with --fallbackbackend on these errors.
+type unavailableError struct {
+	err error
+}
+
+// Error returns the reason the backend is unavailable.
+func (e unavailableError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the reason the backend is unavailable.
+func (e unavailableError) Unwrap() error {
+	return e.err
+}
+
+// backends maps the name of every available backend to the function used to
+// create it.  Backends register themselves from init so new ones can be added
+// without changing the miner.
+var backends = make(map[string]func() (Backend, error))
+
+// registerBackend makes the backend created by newBackend available under
+// name.  It panics if the name is already in use.
+func registerBackend(name string, newBackend func() (Backend, error)) {
+	if _, exists := backends[name]; exists {
+		panic(fmt.Sprintf("backend %q registered twice", name))
+	}
+	backends[name] = newBackend
+}
+
+// supportedBackends returns a sorted slice of the registered backend names.
+func supportedBackends() []string {
+	names := make([]string, 0, len(backends))
+	for name := range backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// newBackend creates the backend registered under name.
+func newBackend(name string) (Backend, error) {
+	create, ok := backends[name]
+	if !ok {
+		return nil, fmt.Errorf("Unknown backend %q -- supported "+
+			"backends %v", name, supportedBackends())
+	}
+	return create()
+}
+
+// newBackendWithFallback creates the backend registered under name, or the
+// one registered under fallback if that backend is unavailable on the system.
+// There is no fallback when it is empty.
+func newBackendWithFallback(name, fallback string) (Backend, error) {
+	backend, err := newBackend(name)
+	var unavailable unavailableError
+	if fallback == "" || !errors.As(err, &unavailable) {
+		return backend, err
+	}
+	minrLog.Warnf("The %s backend is unavailable: %v -- falling back to "+
+		"the %s backend", name, err, fallback)
+	return newBackend(fallback)
+}
@@ -0,0 +1,215 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package blake256 implements the BLAKE-256 and BLAKE-224 hash functions.
+//
+// Besides the standard hash.Hash interface, the package exports the raw block
+// function used by the miner to hash only the final block of a header.
+package blake256
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash"
+)
+
+const (
+	// BlockSize is the block size of BLAKE-256 and BLAKE-224 in bytes.
+	BlockSize = 64
+
+	// Size is the size of a BLAKE-256 checksum in bytes.
+	Size = 32
+
+	// Size224 is the size of a BLAKE-224 checksum in bytes.
+	Size224 = 28
+
+	// SaltSize is the size of a salt in bytes.
+	SaltSize = 16
+)
+
+var (
+	// ErrSaltSize is returned when a salt is not SaltSize bytes long.
+	ErrSaltSize = errors.New("blake256: invalid salt size")
+
+	// ErrRounds is returned for a round count outside 1 to Rounds.
+	ErrRounds = errors.New("blake256: invalid number of rounds")
+)
+
+// digest represents the partial evaluation of a checksum.
+type digest struct {
+	size   int             // checksum size in bytes, Size or Size224
+	h      [8]uint32       // chaining value
+	s      [4]uint32       // salt
+	rounds int             // number of rounds per block
+	t      uint64          // number of message bits compressed into h
+	x      [BlockSize]byte // buffered bytes not compressed yet
+	nx     int             // number of buffered bytes
+}
+
+// New returns a new hash.Hash computing the BLAKE-256 checksum.
+func New() hash.Hash {
+	d := &digest{size: Size, rounds: Rounds}
+	d.Reset()
+	return d
+}
+
+// New224 returns a new hash.Hash computing the BLAKE-224 checksum.
+func New224() hash.Hash {
+	d := &digest{size: Size224, rounds: Rounds}
+	d.Reset()
+	return d
+}
+
+// NewSalted returns a new hash.Hash computing the BLAKE-256 checksum with the
+// given 16 byte salt and number of rounds per block, which must be between 1
+// and Rounds.
+func NewSalted(salt []byte, rounds int) (hash.Hash, error) {
+	d, err := newSalted(Size, salt, rounds)
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// NewSalted224 returns a new hash.Hash computing the BLAKE-224 checksum with
+// the given 16 byte salt and number of rounds per block, which must be between
+// 1 and Rounds.
+func NewSalted224(salt []byte, rounds int) (hash.Hash, error) {
+	d, err := newSalted(Size224, salt, rounds)
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func newSalted(size int, salt []byte, rounds int) (*digest, error) {
+	if len(salt) != SaltSize {
+		return nil, ErrSaltSize
+	}
+	if rounds < 1 || rounds > Rounds {
+		return nil, ErrRounds
+	}
+	d := &digest{size: size, rounds: rounds}
+	for i := range d.s {
+		d.s[i] = binary.BigEndian.Uint32(salt[i*4:])
+	}
+	d.Reset()
+	return d, nil
+}
+
+// Reset resets the hash to its initial state.  The salt and number of rounds
+// are kept.
+func (d *digest) Reset() {
+	if d.size == Size224 {
+		d.h = IV224
+	} else {
+		d.h = IV256
+	}
+	d.t = 0
+	d.nx = 0
+}
+
+// Size returns the number of bytes Sum will return.
+func (d *digest) Size() int {
+	return d.size
+}
+
+// BlockSize returns the hash's underlying block size.
+func (d *digest) BlockSize() int {
+	return BlockSize
+}
+
+// Write adds more data to the running hash.  It never returns an error.
+//
+// Full blocks are compressed right away.  This is fine even when the message
+// ends on a block boundary since the padding then goes into a block of its
+// own.
+func (d *digest) Write(p []byte) (int, error) {
+	nn := len(p)
+	if d.nx > 0 {
+		n := copy(d.x[d.nx:], p)
+		d.nx += n
+		p = p[n:]
+		if d.nx == BlockSize {
+			d.t += BlockSize * 8
+			block(d.h[:], d.x[:], d.t, &d.s, d.rounds)
+			d.nx = 0
+		}
+	}
+	for len(p) >= BlockSize {
+		d.t += BlockSize * 8
+		block(d.h[:], p[:BlockSize], d.t, &d.s, d.rounds)
+		p = p[BlockSize:]
+	}
+	if len(p) > 0 {
+		d.nx = copy(d.x[:], p)
+	}
+	return nn, nil
+}
+
+// Sum appends the current hash to in and returns the resulting slice.  It does
+// not change the underlying hash state.
+func (d *digest) Sum(in []byte) []byte {
+	// Make a copy of d so that the caller can keep writing and summing.
+	d0 := *d
+	sum := d0.checkSum()
+	return append(in, sum[:d.size]...)
+}
+
+// checkSum pads the buffered bytes, compresses the final block(s) and returns
+// the chaining value in bytes.
+func (d *digest) checkSum() [Size]byte {
+	// The padding is a one bit, zeros up to 8 bytes short of a block, a
+	// one bit for BLAKE-256 only and the message length in bits.  When
+	// fewer than 9 bytes are left in the current block it takes up an
+	// extra block.
+	bits := d.t + uint64(d.nx)*8
+	var pad [2 * BlockSize]byte
+	copy(pad[:], d.x[:d.nx])
+	pad[d.nx] = 0x80
+	n := BlockSize
+	if d.nx > BlockSize-9 {
+		n = 2 * BlockSize
+	}
+	if d.size == Size {
+		pad[n-9] |= 0x01
+	}
+	binary.BigEndian.PutUint64(pad[n-8:], bits)
+
+	// The counter holds the number of message bits up to and including
+	// the block, or zero for a block with no message bits at all.
+	t := bits
+	if d.nx == 0 {
+		t = 0
+	}
+	block(d.h[:], pad[:BlockSize], t, &d.s, d.rounds)
+	if n > BlockSize {
+		block(d.h[:], pad[BlockSize:], 0, &d.s, d.rounds)
+	}
+
+	var sum [Size]byte
+	for i, v := range d.h {
+		binary.BigEndian.PutUint32(sum[i*4:], v)
+	}
+	return sum
+}
+
+// Sum256 returns the BLAKE-256 checksum of the data.
+func Sum256(data []byte) [Size]byte {
+	d := digest{size: Size, rounds: Rounds}
+	d.Reset()
+	d.Write(data)
+	return d.checkSum()
+}
+
+// Sum224 returns the BLAKE-224 checksum of the data.
+func Sum224(data []byte) [Size224]byte {
+	d := digest{size: Size224, rounds: Rounds}
+	d.Reset()
+	d.Write(data)
+	sum := d.checkSum()
+	var sum224 [Size224]byte
+	copy(sum224[:], sum[:Size224])
+	return sum224
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blake256
+
+import (
+	"encoding/hex"
+	"hash"
+	"testing"
+)
+
+// hashVectors are the published BLAKE-256 and BLAKE-224 checksums of
+// messages of zero bytes.
+var hashVectors = []struct {
+	len    int
+	sum256 string
+	sum224 string
+}{{
+	len:    0,
+	sum256: "716f6e863f744b9ac22c97ec7b76ea5f5908bc5b2f67c61510bfc4751384ea7a",
+	sum224: "7dc5313b1c04512a174bd6503b89607aecbee0903d40a8a569c94eed",
+}, {
+	len:    1,
+	sum256: "0ce8d4ef4dd7cd8d62dfded9d4edb0a774ae6a41929a74da23109e8f11139c87",
+	sum224: "4504cb0314fb2a4f7a692e696e487912fe3f2468fe312c73a5278ec5",
+}, {
+	len:    72,
+	sum256: "d419bad32d504fb7d44d460c42c5593fe544fa4c135dec31e21bd9abdcc22d41",
+	sum224: "f5aa00dd1cb847e3140372af7b5c46b4888d82c8c0a917913cfb5d04",
+}}
+
+// TestSum checks Sum256 and Sum224 against the published vectors.
+func TestSum(t *testing.T) {
+	for _, v := range hashVectors {
+		msg := make([]byte, v.len)
+		sum256 := Sum256(msg)
+		if got := hex.EncodeToString(sum256[:]); got != v.sum256 {
+			t.Errorf("Sum256 of %d bytes: got %s, want %s", v.len,
+				got, v.sum256)
+		}
+		sum224 := Sum224(msg)
+		if got := hex.EncodeToString(sum224[:]); got != v.sum224 {
+			t.Errorf("Sum224 of %d bytes: got %s, want %s", v.len,
+				got, v.sum224)
+		}
+	}
+}
+
+// TestHash checks the hash.Hash of New and New224 against the published
+// vectors, writing the message at once and a byte at a time.
+func TestHash(t *testing.T) {
+	tests := []struct {
+		name string
+		new  func() hash.Hash
+		size int
+		sum  func(i int) string
+	}{
+		{"New", New, Size, func(i int) string { return hashVectors[i].sum256 }},
+		{"New224", New224, Size224, func(i int) string { return hashVectors[i].sum224 }},
+	}
+	for _, test := range tests {
+		for i, v := range hashVectors {
+			msg := make([]byte, v.len)
+			want := test.sum(i)
+
+			h := test.new()
+			if h.Size() != test.size || h.BlockSize() != BlockSize {
+				t.Fatalf("%s: size %d, block size %d", test.name,
+					h.Size(), h.BlockSize())
+			}
+			h.Write(msg)
+			if got := hex.EncodeToString(h.Sum(nil)); got != want {
+				t.Errorf("%s of %d bytes: got %s, want %s",
+					test.name, v.len, got, want)
+			}
+
+			h.Reset()
+			for j := range msg {
+				h.Write(msg[j : j+1])
+			}
+			if got := hex.EncodeToString(h.Sum(nil)); got != want {
+				t.Errorf("%s of %d bytes written a byte at a "+
+					"time: got %s, want %s", test.name, v.len,
+					got, want)
+			}
+
+			// Summing must not change the state.
+			if got := hex.EncodeToString(h.Sum(nil)); got != want {
+				t.Errorf("%s of %d bytes summed twice: got %s, "+
+					"want %s", test.name, v.len, got, want)
+			}
+		}
+	}
+}
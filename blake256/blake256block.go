@@ -34,19 +34,49 @@ var IV256 = [8]uint32{
 	0x6A09E667, 0xBB67AE85, 0x3C6EF372, 0xA54FF53A,
 	0x510E527F, 0x9B05688C, 0x1F83D9AB, 0x5BE0CD19}
 
+var IV224 = [8]uint32{
+	0xC1059ED8, 0x367CD507, 0x3070DD17, 0xF70E5939,
+	0xFFC00B31, 0x68581511, 0x64F98FA7, 0xBEFA4FA4}
+
+// Rounds is the number of rounds of standard BLAKE-256.
+const Rounds = 14
+
+// zeroSalt is the salt used by Block.
+var zeroSalt [4]uint32
+
 // Block computes a blake256 block, updating the state in 'h' with the data
 // from the block in 'p', assuming a zero salt.
 // h must be 8 uint32's
 // p must be 64 bytes.
 func Block(h []uint32, p []uint8, t uint64) {
+	block(h, p, t, &zeroSalt, Rounds)
+}
+
+// BlockSalted computes a blake256 block like Block, using the salt 's' and
+// only the first 'rounds' rounds.  BLAKE-256 uses 14 rounds and BLAKE-256r8
+// (BLAKE-8) uses 8.
+// rounds must be between 1 and Rounds, otherwise ErrRounds is returned and h
+// is left alone.
+func BlockSalted(h []uint32, p []uint8, t uint64, s *[4]uint32, rounds int) error {
+	if rounds < 1 || rounds > Rounds {
+		return ErrRounds
+	}
+	block(h, p, t, s, rounds)
+	return nil
+}
+
+// block is the compression function shared by Block and BlockSalted.  The
+// rounds are fully unrolled and the remaining ones are skipped once the
+// requested number has run.
+func block(h []uint32, p []uint8, t uint64, s *[4]uint32, rounds int) {
 	h0, h1, h2, h3, h4, h5, h6, h7 := h[0], h[1], h[2], h[3], h[4], h[5], h[6], h[7]
 
 	v0, v1, v2, v3, v4, v5, v6, v7 := h0, h1, h2, h3, h4, h5, h6, h7
 
-	v8 := uint32(cst0)
-	v9 := uint32(cst1)
-	v10 := uint32(cst2)
-	v11 := uint32(cst3)
+	v8 := s[0] ^ cst0
+	v9 := s[1] ^ cst1
+	v10 := s[2] ^ cst2
+	v11 := s[3] ^ cst3
 	v12 := uint32(cst4)
 	v13 := uint32(cst5)
 	v14 := uint32(cst6)
@@ -189,6 +219,10 @@ func Block(h []uint32, p []uint8, t uint64) {
 	v5 ^= v10
 	v5 = v5<<(32-7) | v5>>7
 
+	if rounds < 2 {
+		goto finalize
+	}
+
 	// Round 2.
 	v0 += m[14] ^ cst10
 	v0 += v4
@@ -303,6 +337,10 @@ func Block(h []uint32, p []uint8, t uint64) {
 	v5 ^= v10
 	v5 = v5<<(32-7) | v5>>7
 
+	if rounds < 3 {
+		goto finalize
+	}
+
 	// Round 3.
 	v0 += m[11] ^ cst8
 	v0 += v4
@@ -417,6 +455,10 @@ func Block(h []uint32, p []uint8, t uint64) {
 	v5 ^= v10
 	v5 = v5<<(32-7) | v5>>7
 
+	if rounds < 4 {
+		goto finalize
+	}
+
 	// Round 4.
 	v0 += m[7] ^ cst9
 	v0 += v4
@@ -531,6 +573,10 @@ func Block(h []uint32, p []uint8, t uint64) {
 	v5 ^= v10
 	v5 = v5<<(32-7) | v5>>7
 
+	if rounds < 5 {
+		goto finalize
+	}
+
 	// Round 5.
 	v0 += m[9] ^ cst0
 	v0 += v4
@@ -645,6 +691,10 @@ func Block(h []uint32, p []uint8, t uint64) {
 	v5 ^= v10
 	v5 = v5<<(32-7) | v5>>7
 
+	if rounds < 6 {
+		goto finalize
+	}
+
 	// Round 6.
 	v0 += m[2] ^ cst12
 	v0 += v4
@@ -759,6 +809,10 @@ func Block(h []uint32, p []uint8, t uint64) {
 	v5 ^= v10
 	v5 = v5<<(32-7) | v5>>7
 
+	if rounds < 7 {
+		goto finalize
+	}
+
 	// Round 7.
 	v0 += m[12] ^ cst5
 	v0 += v4
@@ -873,6 +927,10 @@ func Block(h []uint32, p []uint8, t uint64) {
 	v5 ^= v10
 	v5 = v5<<(32-7) | v5>>7
 
+	if rounds < 8 {
+		goto finalize
+	}
+
 	// Round 8.
 	v0 += m[13] ^ cst11
 	v0 += v4
@@ -987,6 +1045,10 @@ func Block(h []uint32, p []uint8, t uint64) {
 	v5 ^= v10
 	v5 = v5<<(32-7) | v5>>7
 
+	if rounds < 9 {
+		goto finalize
+	}
+
 	// Round 9.
 	v0 += m[6] ^ cst15
 	v0 += v4
@@ -1101,6 +1163,10 @@ func Block(h []uint32, p []uint8, t uint64) {
 	v5 ^= v10
 	v5 = v5<<(32-7) | v5>>7
 
+	if rounds < 10 {
+		goto finalize
+	}
+
 	// Round 10.
 	v0 += m[10] ^ cst2
 	v0 += v4
@@ -1215,6 +1281,10 @@ func Block(h []uint32, p []uint8, t uint64) {
 	v5 ^= v10
 	v5 = v5<<(32-7) | v5>>7
 
+	if rounds < 11 {
+		goto finalize
+	}
+
 	// Round 11.
 	v0 += m[0] ^ cst1
 	v0 += v4
@@ -1329,6 +1399,10 @@ func Block(h []uint32, p []uint8, t uint64) {
 	v5 ^= v10
 	v5 = v5<<(32-7) | v5>>7
 
+	if rounds < 12 {
+		goto finalize
+	}
+
 	// Round 12.
 	v0 += m[14] ^ cst10
 	v0 += v4
@@ -1443,6 +1517,10 @@ func Block(h []uint32, p []uint8, t uint64) {
 	v5 ^= v10
 	v5 = v5<<(32-7) | v5>>7
 
+	if rounds < 13 {
+		goto finalize
+	}
+
 	// Round 13.
 	v0 += m[11] ^ cst8
 	v0 += v4
@@ -1557,6 +1635,10 @@ func Block(h []uint32, p []uint8, t uint64) {
 	v5 ^= v10
 	v5 = v5<<(32-7) | v5>>7
 
+	if rounds < 14 {
+		goto finalize
+	}
+
 	// Round 14.
 	v0 += m[7] ^ cst9
 	v0 += v4
@@ -1671,14 +1753,15 @@ func Block(h []uint32, p []uint8, t uint64) {
 	v5 ^= v10
 	v5 = v5<<(32-7) | v5>>7
 
-	h0 ^= v0 ^ v8
-	h1 ^= v1 ^ v9
-	h2 ^= v2 ^ v10
-	h3 ^= v3 ^ v11
-	h4 ^= v4 ^ v12
-	h5 ^= v5 ^ v13
-	h6 ^= v6 ^ v14
-	h7 ^= v7 ^ v15
+finalize:
+	h0 ^= s[0] ^ v0 ^ v8
+	h1 ^= s[1] ^ v1 ^ v9
+	h2 ^= s[2] ^ v2 ^ v10
+	h3 ^= s[3] ^ v3 ^ v11
+	h4 ^= s[0] ^ v4 ^ v12
+	h5 ^= s[1] ^ v5 ^ v13
+	h6 ^= s[2] ^ v6 ^ v14
+	h7 ^= s[3] ^ v7 ^ v15
 
 	h[0], h[1], h[2], h[3], h[4], h[5], h[6], h[7] = h0, h1, h2, h3, h4, h5, h6, h7
 }
@@ -0,0 +1,250 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blake256
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"hash"
+	"math/bits"
+	"math/rand"
+	"testing"
+)
+
+// refSigma and refConstants are the permutations and constants of the BLAKE
+// specification.
+var (
+	refSigma = [10][16]int{
+		{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+		{14, 10, 4, 8, 9, 15, 13, 6, 1, 12, 0, 2, 11, 7, 5, 3},
+		{11, 8, 12, 0, 5, 2, 15, 13, 10, 14, 3, 6, 7, 1, 9, 4},
+		{7, 9, 3, 1, 13, 12, 11, 14, 2, 6, 5, 10, 4, 0, 15, 8},
+		{9, 0, 5, 7, 2, 4, 10, 15, 14, 1, 11, 12, 6, 8, 3, 13},
+		{2, 12, 6, 10, 0, 11, 8, 3, 4, 13, 7, 5, 15, 14, 1, 9},
+		{12, 5, 1, 15, 14, 13, 4, 10, 0, 7, 6, 3, 9, 2, 8, 11},
+		{13, 11, 7, 14, 12, 1, 3, 9, 5, 0, 15, 4, 8, 6, 2, 10},
+		{6, 15, 14, 9, 11, 3, 0, 8, 12, 2, 13, 7, 1, 4, 10, 5},
+		{10, 2, 8, 4, 7, 6, 1, 5, 15, 11, 9, 14, 3, 12, 13, 0},
+	}
+	refConstants = [16]uint32{
+		0x243F6A88, 0x85A308D3, 0x13198A2E, 0x03707344,
+		0xA4093822, 0x299F31D0, 0x082EFA98, 0xEC4E6C89,
+		0x452821E6, 0x38D01377, 0xBE5466CF, 0x34E90C6C,
+		0xC0AC29B7, 0xC97C50DD, 0x3F84D5B5, 0xB5470917,
+	}
+)
+
+// refBlock is the compression function written plainly from the
+// specification, which the unrolled one is checked against.
+func refBlock(h *[8]uint32, p []byte, t uint64, s *[4]uint32, rounds int) {
+	var m [16]uint32
+	for i := range m {
+		m[i] = binary.BigEndian.Uint32(p[i*4:])
+	}
+
+	var v [16]uint32
+	copy(v[:8], h[:])
+	for i := 0; i < 4; i++ {
+		v[8+i] = s[i] ^ refConstants[i]
+	}
+	v[12] = uint32(t) ^ refConstants[4]
+	v[13] = uint32(t) ^ refConstants[5]
+	v[14] = uint32(t>>32) ^ refConstants[6]
+	v[15] = uint32(t>>32) ^ refConstants[7]
+
+	g := func(r, i, a, b, c, d int) {
+		sigma := &refSigma[r%10]
+		x, y := sigma[2*i], sigma[2*i+1]
+		v[a] += v[b] + (m[x] ^ refConstants[y])
+		v[d] = bits.RotateLeft32(v[d]^v[a], -16)
+		v[c] += v[d]
+		v[b] = bits.RotateLeft32(v[b]^v[c], -12)
+		v[a] += v[b] + (m[y] ^ refConstants[x])
+		v[d] = bits.RotateLeft32(v[d]^v[a], -8)
+		v[c] += v[d]
+		v[b] = bits.RotateLeft32(v[b]^v[c], -7)
+	}
+	for r := 0; r < rounds; r++ {
+		g(r, 0, 0, 4, 8, 12)
+		g(r, 1, 1, 5, 9, 13)
+		g(r, 2, 2, 6, 10, 14)
+		g(r, 3, 3, 7, 11, 15)
+		g(r, 4, 0, 5, 10, 15)
+		g(r, 5, 1, 6, 11, 12)
+		g(r, 6, 2, 7, 8, 13)
+		g(r, 7, 3, 4, 9, 14)
+	}
+
+	for i := range h {
+		h[i] ^= s[i%4] ^ v[i] ^ v[i+8]
+	}
+}
+
+// TestBlockSalted checks every round count with random salts against the
+// compression function of the specification.
+func TestBlockSalted(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for rounds := 1; rounds <= Rounds; rounds++ {
+		for i := 0; i < 50; i++ {
+			var h [8]uint32
+			var s [4]uint32
+			var p [BlockSize]byte
+			for j := range h {
+				h[j] = r.Uint32()
+			}
+			for j := range s {
+				s[j] = r.Uint32()
+			}
+			r.Read(p[:])
+			counter := r.Uint64()
+
+			want := h
+			refBlock(&want, p[:], counter, &s, rounds)
+			got := h
+			err := BlockSalted(got[:], p[:], counter, &s, rounds)
+			if err != nil {
+				t.Fatalf("%d rounds: %v", rounds, err)
+			}
+			if got != want {
+				t.Fatalf("%d rounds: got %08x, want %08x",
+					rounds, got, want)
+			}
+		}
+	}
+
+	// Block is BlockSalted with a zero salt and every round.
+	var p [BlockSize]byte
+	want := IV256
+	refBlock(&want, p[:], 512, &zeroSalt, Rounds)
+	h := IV256
+	Block(h[:], p[:], 512)
+	if h != want {
+		t.Errorf("Block: got %08x, want %08x", h, want)
+	}
+
+	for _, rounds := range []int{-1, 0, Rounds + 1} {
+		h := IV256
+		if err := BlockSalted(h[:], p[:], 0, &zeroSalt, rounds); err != ErrRounds {
+			t.Errorf("%d rounds: got %v, want %v", rounds, err,
+				ErrRounds)
+		}
+		if h != IV256 {
+			t.Errorf("%d rounds: state changed", rounds)
+		}
+	}
+}
+
+// TestSalted checks NewSalted and NewSalted224 with all 14 rounds against
+// checksums computed with NewSalt and New224Salt of
+// github.com/decred/dcrd/crypto/blake256.
+func TestSalted(t *testing.T) {
+	salt := []byte("0123456789abcdef")
+	tests := []struct {
+		len    int
+		sum256 string
+		sum224 string
+	}{{
+		len:    0,
+		sum256: "2b5ada2d19f8491ffecec44287b5259f31f665cb26cab907ab5ff9993ba58a6c",
+		sum224: "ed38760fced9ab15d24bd6ea56c085607fb101e3e810e8fbbe421957",
+	}, {
+		len:    1,
+		sum256: "db5c735d21ca6c9aac40b66e7ee09f12479263525f6e3dbed6c50a5f10b68693",
+		sum224: "ad90ccd6435ff6a0f3feb6f8c78fe60c77e79fd406bd2fdc5f748738",
+	}, {
+		len:    72,
+		sum256: "696a50e2b954659e115169fc38e4c1af5eefd76b5dd6658ac8960f803eddc8a1",
+		sum224: "cec95c7dcc08c177d90fdb41dd218d16ba85f2df2d9f61fd976b00e2",
+	}, {
+		len:    180,
+		sum256: "9e9b95814d84d3a0da5ac891593e372cf68b52af338408265799e72a0a285a69",
+		sum224: "b9f885641e7b61b56849dba58f60a1e2f60fdd90b27e5b616d2e654f",
+	}}
+
+	for _, test := range tests {
+		msg := make([]byte, test.len)
+		for i := range msg {
+			msg[i] = byte(i)
+		}
+
+		h, err := NewSalted(salt, Rounds)
+		if err != nil {
+			t.Fatalf("NewSalted: %v", err)
+		}
+		h.Write(msg)
+		if got := hex.EncodeToString(h.Sum(nil)); got != test.sum256 {
+			t.Errorf("NewSalted of %d bytes: got %s, want %s",
+				test.len, got, test.sum256)
+		}
+
+		h, err = NewSalted224(salt, Rounds)
+		if err != nil {
+			t.Fatalf("NewSalted224: %v", err)
+		}
+		h.Write(msg)
+		if got := hex.EncodeToString(h.Sum(nil)); got != test.sum224 {
+			t.Errorf("NewSalted224 of %d bytes: got %s, want %s",
+				test.len, got, test.sum224)
+		}
+	}
+
+	if _, err := NewSalted(salt[:15], Rounds); err != ErrSaltSize {
+		t.Errorf("short salt: got %v, want %v", err, ErrSaltSize)
+	}
+	for _, rounds := range []int{0, Rounds + 1} {
+		if _, err := NewSalted224(salt, rounds); err != ErrRounds {
+			t.Errorf("%d rounds: got %v, want %v", rounds, err,
+				ErrRounds)
+		}
+	}
+}
+
+// TestSalted8 checks an 8 round checksum, which dcrd does not implement,
+// block by block against the compression function of the specification.
+func TestSalted8(t *testing.T) {
+	salt := []byte("0123456789abcdef")
+	var s [4]uint32
+	for i := range s {
+		s[i] = binary.BigEndian.Uint32(salt[i*4:])
+	}
+
+	// 180 bytes take three blocks, the last one holding the padding.
+	msg := make([]byte, 180)
+	for i := range msg {
+		msg[i] = byte(i)
+	}
+	var padded [3 * BlockSize]byte
+	copy(padded[:], msg)
+	padded[180] = 0x80
+	padded[len(padded)-9] |= 0x01
+	binary.BigEndian.PutUint64(padded[len(padded)-8:], 180*8)
+
+	want := IV256
+	refBlock(&want, padded[0:], 512, &s, 8)
+	refBlock(&want, padded[64:], 1024, &s, 8)
+	refBlock(&want, padded[128:], 1440, &s, 8)
+	var wantSum [Size]byte
+	for i, v := range want {
+		binary.BigEndian.PutUint32(wantSum[i*4:], v)
+	}
+
+	for _, write := range []func(h hash.Hash){
+		func(h hash.Hash) { h.Write(msg) },
+		func(h hash.Hash) {
+			for i := range msg {
+				h.Write(msg[i : i+1])
+			}
+		},
+	} {
+		h, err := NewSalted(salt, 8)
+		if err != nil {
+			t.Fatalf("NewSalted: %v", err)
+		}
+		write(h)
+		if got := h.Sum(nil); string(got) != string(wantSum[:]) {
+			t.Errorf("got %x, want %x", got, wantSum)
+		}
+	}
+}
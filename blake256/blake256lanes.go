@@ -0,0 +1,60 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blake256
+
+import "encoding/binary"
+
+// Block4 computes four BLAKE-256 blocks at once, assuming a zero salt.  All
+// four lanes start from the chaining value in h and hash the message words in
+// m with counter t, except that word nonceWord of m is replaced by nonces[i]
+// in lane i.  Word j of the resulting chaining value of lane i is written to
+// out[j][i].
+//
+// This is meant for searching nonces in the final block of a message, where
+// only a single word changes between hashes.  The results are identical to
+// calling Block on every lane.
+func Block4(out *[8][4]uint32, h *[8]uint32, m *[16]uint32, nonceWord int, nonces *[4]uint32, t uint64) {
+	block4(out, h, m, nonceWord, nonces, t)
+}
+
+// Block8 is the eight lane version of Block4.
+func Block8(out *[8][8]uint32, h *[8]uint32, m *[16]uint32, nonceWord int, nonces *[8]uint32, t uint64) {
+	block8(out, h, m, nonceWord, nonces, t)
+}
+
+// blockLanesGeneric hashes every lane in turn using Block.  out must hold
+// eight slices of len(nonces) words.
+func blockLanesGeneric(out [8][]uint32, h *[8]uint32, m *[16]uint32, nonceWord int, nonces []uint32, t uint64) {
+	var p [64]byte
+	for i := 0; i < 16; i++ {
+		binary.BigEndian.PutUint32(p[i*4:], m[i])
+	}
+	for lane, nonce := range nonces {
+		binary.BigEndian.PutUint32(p[nonceWord*4:], nonce)
+		state := *h
+		Block(state[:], p[:], t)
+		for j := 0; j < 8; j++ {
+			out[j][lane] = state[j]
+		}
+	}
+}
+
+// block4Generic is the portable implementation of Block4.
+func block4Generic(out *[8][4]uint32, h *[8]uint32, m *[16]uint32, nonceWord int, nonces *[4]uint32, t uint64) {
+	var o [8][]uint32
+	for j := range o {
+		o[j] = out[j][:]
+	}
+	blockLanesGeneric(o, h, m, nonceWord, nonces[:], t)
+}
+
+// block8Generic is the portable implementation of Block8.
+func block8Generic(out *[8][8]uint32, h *[8]uint32, m *[16]uint32, nonceWord int, nonces *[8]uint32, t uint64) {
+	var o [8][]uint32
+	for j := range o {
+		o[j] = out[j][:]
+	}
+	blockLanesGeneric(o, h, m, nonceWord, nonces[:], t)
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blake256
+
+var (
+	// useSSSE3 and useAVX2 select the assembly implementations of the
+	// multi-lane block functions supported by the cpu.
+	useSSSE3 = hasSSSE3()
+	useAVX2  = hasAVX2()
+)
+
+//go:noescape
+func block4SSSE3(out *[8][4]uint32, h *[8]uint32, msg *[16][4]uint32, t uint64)
+
+//go:noescape
+func block8AVX2(out *[8][8]uint32, h *[8]uint32, msg *[16][8]uint32, t uint64)
+
+func block4(out *[8][4]uint32, h *[8]uint32, m *[16]uint32, nonceWord int, nonces *[4]uint32, t uint64) {
+	if !useSSSE3 {
+		block4Generic(out, h, m, nonceWord, nonces, t)
+		return
+	}
+
+	// The assembly takes one vector per message word.
+	var msg [16][4]uint32
+	for i, w := range m {
+		msg[i] = [4]uint32{w, w, w, w}
+	}
+	msg[nonceWord] = *nonces
+	block4SSSE3(out, h, &msg, t)
+}
+
+func block8(out *[8][8]uint32, h *[8]uint32, m *[16]uint32, nonceWord int, nonces *[8]uint32, t uint64) {
+	switch {
+	case useAVX2:
+		// The assembly takes one vector per message word.
+		var msg [16][8]uint32
+		for i, w := range m {
+			msg[i] = [8]uint32{w, w, w, w, w, w, w, w}
+		}
+		msg[nonceWord] = *nonces
+		block8AVX2(out, h, &msg, t)
+
+	case useSSSE3:
+		// Hash the lanes four at a time.
+		var lo, hi [8][4]uint32
+		var nlo, nhi [4]uint32
+		copy(nlo[:], nonces[:4])
+		copy(nhi[:], nonces[4:])
+		block4(&lo, h, m, nonceWord, &nlo, t)
+		block4(&hi, h, m, nonceWord, &nhi, t)
+		for j := 0; j < 8; j++ {
+			copy(out[j][:4], lo[j][:])
+			copy(out[j][4:], hi[j][:])
+		}
+
+	default:
+		block8Generic(out, h, m, nonceWord, nonces, t)
+	}
+}
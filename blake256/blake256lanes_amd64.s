@@ -0,0 +1,619 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+#include "textflag.h"
+
+// The lanes are kept transposed: every vector holds the same state word for
+// all lanes, so the sixteen words of the state take up sixteen vectors.  They
+// don't fit in the registers together with the temporaries, so the state
+// lives on the stack and each G step loads and stores the four words it
+// works on.
+//
+// Register usage:
+//   DI - output chaining values
+//   DX - input chaining value
+//   SI - expanded message, one vector per word
+//   BX - round constants, one broadcast 16 byte entry per constant
+//   X14/Y14, X15/Y15 - byte shuffle masks for the 16 and 8 bit rotations
+
+// The round constants, each repeated in all four 32-bit words.
+DATA cst<>+0x00(SB)/8, $0x243f6a88243f6a88
+DATA cst<>+0x08(SB)/8, $0x243f6a88243f6a88
+DATA cst<>+0x10(SB)/8, $0x85a308d385a308d3
+DATA cst<>+0x18(SB)/8, $0x85a308d385a308d3
+DATA cst<>+0x20(SB)/8, $0x13198a2e13198a2e
+DATA cst<>+0x28(SB)/8, $0x13198a2e13198a2e
+DATA cst<>+0x30(SB)/8, $0x0370734403707344
+DATA cst<>+0x38(SB)/8, $0x0370734403707344
+DATA cst<>+0x40(SB)/8, $0xa4093822a4093822
+DATA cst<>+0x48(SB)/8, $0xa4093822a4093822
+DATA cst<>+0x50(SB)/8, $0x299f31d0299f31d0
+DATA cst<>+0x58(SB)/8, $0x299f31d0299f31d0
+DATA cst<>+0x60(SB)/8, $0x082efa98082efa98
+DATA cst<>+0x68(SB)/8, $0x082efa98082efa98
+DATA cst<>+0x70(SB)/8, $0xec4e6c89ec4e6c89
+DATA cst<>+0x78(SB)/8, $0xec4e6c89ec4e6c89
+DATA cst<>+0x80(SB)/8, $0x452821e6452821e6
+DATA cst<>+0x88(SB)/8, $0x452821e6452821e6
+DATA cst<>+0x90(SB)/8, $0x38d0137738d01377
+DATA cst<>+0x98(SB)/8, $0x38d0137738d01377
+DATA cst<>+0xa0(SB)/8, $0xbe5466cfbe5466cf
+DATA cst<>+0xa8(SB)/8, $0xbe5466cfbe5466cf
+DATA cst<>+0xb0(SB)/8, $0x34e90c6c34e90c6c
+DATA cst<>+0xb8(SB)/8, $0x34e90c6c34e90c6c
+DATA cst<>+0xc0(SB)/8, $0xc0ac29b7c0ac29b7
+DATA cst<>+0xc8(SB)/8, $0xc0ac29b7c0ac29b7
+DATA cst<>+0xd0(SB)/8, $0xc97c50ddc97c50dd
+DATA cst<>+0xd8(SB)/8, $0xc97c50ddc97c50dd
+DATA cst<>+0xe0(SB)/8, $0x3f84d5b53f84d5b5
+DATA cst<>+0xe8(SB)/8, $0x3f84d5b53f84d5b5
+DATA cst<>+0xf0(SB)/8, $0xb5470917b5470917
+DATA cst<>+0xf8(SB)/8, $0xb5470917b5470917
+GLOBL cst<>(SB), (NOPTR+RODATA), $256
+
+// Byte shuffle masks rotating every 32-bit word right by 16 and 8 bits.
+DATA rot16<>+0x00(SB)/8, $0x0504070601000302
+DATA rot16<>+0x08(SB)/8, $0x0d0c0f0e09080b0a
+GLOBL rot16<>(SB), (NOPTR+RODATA), $16
+
+DATA rot8<>+0x00(SB)/8, $0x0407060500030201
+DATA rot8<>+0x08(SB)/8, $0x0c0f0e0d080b0a09
+GLOBL rot8<>(SB), (NOPTR+RODATA), $16
+
+#define ROTR4(x, n, t) \
+	MOVO x, t; \
+	PSRLL $n, x; \
+	PSLLL $(32-n), t; \
+	POR t, x
+
+// G4 performs the G function on state words a, b, c and d of all four lanes
+// using message words x and y.
+#define G4(a, b, c, d, x, y) \
+	MOVOU (a*16)(SP), X0; \
+	MOVOU (b*16)(SP), X1; \
+	MOVOU (c*16)(SP), X2; \
+	MOVOU (d*16)(SP), X3; \
+	MOVOU (x*16)(SI), X4; \
+	MOVOU (y*16)(BX), X5; \
+	PXOR X5, X4; \
+	PADDL X4, X0; \
+	PADDL X1, X0; \
+	PXOR X0, X3; \
+	PSHUFB X14, X3; \
+	PADDL X3, X2; \
+	PXOR X2, X1; \
+	ROTR4(X1, 12, X4); \
+	MOVOU (y*16)(SI), X4; \
+	MOVOU (x*16)(BX), X5; \
+	PXOR X5, X4; \
+	PADDL X4, X0; \
+	PADDL X1, X0; \
+	PXOR X0, X3; \
+	PSHUFB X15, X3; \
+	PADDL X3, X2; \
+	PXOR X2, X1; \
+	ROTR4(X1, 7, X4); \
+	MOVOU X0, (a*16)(SP); \
+	MOVOU X1, (b*16)(SP); \
+	MOVOU X2, (c*16)(SP); \
+	MOVOU X3, (d*16)(SP)
+
+// func block4SSSE3(out *[8][4]uint32, h *[8]uint32, msg *[16][4]uint32, t uint64)
+TEXT ·block4SSSE3(SB), NOSPLIT, $256-32
+	MOVQ out+0(FP), DI
+	MOVQ h+8(FP), DX
+	MOVQ msg+16(FP), SI
+	LEAQ cst<>(SB), BX
+	MOVOU rot16<>(SB), X14
+	MOVOU rot8<>(SB), X15
+
+	// v0..v7 = h
+	MOVL (0*4)(DX), AX
+	MOVQ AX, X0
+	PSHUFD $0, X0, X0
+	MOVOU X0, (0*16)(SP)
+	MOVL (1*4)(DX), AX
+	MOVQ AX, X0
+	PSHUFD $0, X0, X0
+	MOVOU X0, (1*16)(SP)
+	MOVL (2*4)(DX), AX
+	MOVQ AX, X0
+	PSHUFD $0, X0, X0
+	MOVOU X0, (2*16)(SP)
+	MOVL (3*4)(DX), AX
+	MOVQ AX, X0
+	PSHUFD $0, X0, X0
+	MOVOU X0, (3*16)(SP)
+	MOVL (4*4)(DX), AX
+	MOVQ AX, X0
+	PSHUFD $0, X0, X0
+	MOVOU X0, (4*16)(SP)
+	MOVL (5*4)(DX), AX
+	MOVQ AX, X0
+	PSHUFD $0, X0, X0
+	MOVOU X0, (5*16)(SP)
+	MOVL (6*4)(DX), AX
+	MOVQ AX, X0
+	PSHUFD $0, X0, X0
+	MOVOU X0, (6*16)(SP)
+	MOVL (7*4)(DX), AX
+	MOVQ AX, X0
+	PSHUFD $0, X0, X0
+	MOVOU X0, (7*16)(SP)
+
+	// v8..v11 = cst0..cst3
+	MOVOU (0*16)(BX), X0
+	MOVOU X0, (8*16)(SP)
+	MOVOU (1*16)(BX), X0
+	MOVOU X0, (9*16)(SP)
+	MOVOU (2*16)(BX), X0
+	MOVOU X0, (10*16)(SP)
+	MOVOU (3*16)(BX), X0
+	MOVOU X0, (11*16)(SP)
+
+	// v12..v15 = cst4..cst7 xor the counter.
+	MOVQ t+24(FP), X6
+	PSHUFD $0x00, X6, X7
+	PSHUFD $0x55, X6, X6
+	MOVOU (4*16)(BX), X0
+	PXOR X7, X0
+	MOVOU X0, (12*16)(SP)
+	MOVOU (5*16)(BX), X0
+	PXOR X7, X0
+	MOVOU X0, (13*16)(SP)
+	MOVOU (6*16)(BX), X0
+	PXOR X6, X0
+	MOVOU X0, (14*16)(SP)
+	MOVOU (7*16)(BX), X0
+	PXOR X6, X0
+	MOVOU X0, (15*16)(SP)
+
+	// Round 1.
+	G4(0, 4, 8, 12, 0, 1)
+	G4(1, 5, 9, 13, 2, 3)
+	G4(2, 6, 10, 14, 4, 5)
+	G4(3, 7, 11, 15, 6, 7)
+	G4(0, 5, 10, 15, 8, 9)
+	G4(1, 6, 11, 12, 10, 11)
+	G4(2, 7, 8, 13, 12, 13)
+	G4(3, 4, 9, 14, 14, 15)
+	// Round 2.
+	G4(0, 4, 8, 12, 14, 10)
+	G4(1, 5, 9, 13, 4, 8)
+	G4(2, 6, 10, 14, 9, 15)
+	G4(3, 7, 11, 15, 13, 6)
+	G4(0, 5, 10, 15, 1, 12)
+	G4(1, 6, 11, 12, 0, 2)
+	G4(2, 7, 8, 13, 11, 7)
+	G4(3, 4, 9, 14, 5, 3)
+	// Round 3.
+	G4(0, 4, 8, 12, 11, 8)
+	G4(1, 5, 9, 13, 12, 0)
+	G4(2, 6, 10, 14, 5, 2)
+	G4(3, 7, 11, 15, 15, 13)
+	G4(0, 5, 10, 15, 10, 14)
+	G4(1, 6, 11, 12, 3, 6)
+	G4(2, 7, 8, 13, 7, 1)
+	G4(3, 4, 9, 14, 9, 4)
+	// Round 4.
+	G4(0, 4, 8, 12, 7, 9)
+	G4(1, 5, 9, 13, 3, 1)
+	G4(2, 6, 10, 14, 13, 12)
+	G4(3, 7, 11, 15, 11, 14)
+	G4(0, 5, 10, 15, 2, 6)
+	G4(1, 6, 11, 12, 5, 10)
+	G4(2, 7, 8, 13, 4, 0)
+	G4(3, 4, 9, 14, 15, 8)
+	// Round 5.
+	G4(0, 4, 8, 12, 9, 0)
+	G4(1, 5, 9, 13, 5, 7)
+	G4(2, 6, 10, 14, 2, 4)
+	G4(3, 7, 11, 15, 10, 15)
+	G4(0, 5, 10, 15, 14, 1)
+	G4(1, 6, 11, 12, 11, 12)
+	G4(2, 7, 8, 13, 6, 8)
+	G4(3, 4, 9, 14, 3, 13)
+	// Round 6.
+	G4(0, 4, 8, 12, 2, 12)
+	G4(1, 5, 9, 13, 6, 10)
+	G4(2, 6, 10, 14, 0, 11)
+	G4(3, 7, 11, 15, 8, 3)
+	G4(0, 5, 10, 15, 4, 13)
+	G4(1, 6, 11, 12, 7, 5)
+	G4(2, 7, 8, 13, 15, 14)
+	G4(3, 4, 9, 14, 1, 9)
+	// Round 7.
+	G4(0, 4, 8, 12, 12, 5)
+	G4(1, 5, 9, 13, 1, 15)
+	G4(2, 6, 10, 14, 14, 13)
+	G4(3, 7, 11, 15, 4, 10)
+	G4(0, 5, 10, 15, 0, 7)
+	G4(1, 6, 11, 12, 6, 3)
+	G4(2, 7, 8, 13, 9, 2)
+	G4(3, 4, 9, 14, 8, 11)
+	// Round 8.
+	G4(0, 4, 8, 12, 13, 11)
+	G4(1, 5, 9, 13, 7, 14)
+	G4(2, 6, 10, 14, 12, 1)
+	G4(3, 7, 11, 15, 3, 9)
+	G4(0, 5, 10, 15, 5, 0)
+	G4(1, 6, 11, 12, 15, 4)
+	G4(2, 7, 8, 13, 8, 6)
+	G4(3, 4, 9, 14, 2, 10)
+	// Round 9.
+	G4(0, 4, 8, 12, 6, 15)
+	G4(1, 5, 9, 13, 14, 9)
+	G4(2, 6, 10, 14, 11, 3)
+	G4(3, 7, 11, 15, 0, 8)
+	G4(0, 5, 10, 15, 12, 2)
+	G4(1, 6, 11, 12, 13, 7)
+	G4(2, 7, 8, 13, 1, 4)
+	G4(3, 4, 9, 14, 10, 5)
+	// Round 10.
+	G4(0, 4, 8, 12, 10, 2)
+	G4(1, 5, 9, 13, 8, 4)
+	G4(2, 6, 10, 14, 7, 6)
+	G4(3, 7, 11, 15, 1, 5)
+	G4(0, 5, 10, 15, 15, 11)
+	G4(1, 6, 11, 12, 9, 14)
+	G4(2, 7, 8, 13, 3, 12)
+	G4(3, 4, 9, 14, 13, 0)
+	// Round 11.
+	G4(0, 4, 8, 12, 0, 1)
+	G4(1, 5, 9, 13, 2, 3)
+	G4(2, 6, 10, 14, 4, 5)
+	G4(3, 7, 11, 15, 6, 7)
+	G4(0, 5, 10, 15, 8, 9)
+	G4(1, 6, 11, 12, 10, 11)
+	G4(2, 7, 8, 13, 12, 13)
+	G4(3, 4, 9, 14, 14, 15)
+	// Round 12.
+	G4(0, 4, 8, 12, 14, 10)
+	G4(1, 5, 9, 13, 4, 8)
+	G4(2, 6, 10, 14, 9, 15)
+	G4(3, 7, 11, 15, 13, 6)
+	G4(0, 5, 10, 15, 1, 12)
+	G4(1, 6, 11, 12, 0, 2)
+	G4(2, 7, 8, 13, 11, 7)
+	G4(3, 4, 9, 14, 5, 3)
+	// Round 13.
+	G4(0, 4, 8, 12, 11, 8)
+	G4(1, 5, 9, 13, 12, 0)
+	G4(2, 6, 10, 14, 5, 2)
+	G4(3, 7, 11, 15, 15, 13)
+	G4(0, 5, 10, 15, 10, 14)
+	G4(1, 6, 11, 12, 3, 6)
+	G4(2, 7, 8, 13, 7, 1)
+	G4(3, 4, 9, 14, 9, 4)
+	// Round 14.
+	G4(0, 4, 8, 12, 7, 9)
+	G4(1, 5, 9, 13, 3, 1)
+	G4(2, 6, 10, 14, 13, 12)
+	G4(3, 7, 11, 15, 11, 14)
+	G4(0, 5, 10, 15, 2, 6)
+	G4(1, 6, 11, 12, 5, 10)
+	G4(2, 7, 8, 13, 4, 0)
+	G4(3, 4, 9, 14, 15, 8)
+
+	// out = h ^ v0..v7 ^ v8..v15
+	MOVL (0*4)(DX), AX
+	MOVQ AX, X0
+	PSHUFD $0, X0, X0
+	MOVOU (0*16)(SP), X1
+	PXOR X1, X0
+	MOVOU (8*16)(SP), X1
+	PXOR X1, X0
+	MOVOU X0, (0*16)(DI)
+	MOVL (1*4)(DX), AX
+	MOVQ AX, X0
+	PSHUFD $0, X0, X0
+	MOVOU (1*16)(SP), X1
+	PXOR X1, X0
+	MOVOU (9*16)(SP), X1
+	PXOR X1, X0
+	MOVOU X0, (1*16)(DI)
+	MOVL (2*4)(DX), AX
+	MOVQ AX, X0
+	PSHUFD $0, X0, X0
+	MOVOU (2*16)(SP), X1
+	PXOR X1, X0
+	MOVOU (10*16)(SP), X1
+	PXOR X1, X0
+	MOVOU X0, (2*16)(DI)
+	MOVL (3*4)(DX), AX
+	MOVQ AX, X0
+	PSHUFD $0, X0, X0
+	MOVOU (3*16)(SP), X1
+	PXOR X1, X0
+	MOVOU (11*16)(SP), X1
+	PXOR X1, X0
+	MOVOU X0, (3*16)(DI)
+	MOVL (4*4)(DX), AX
+	MOVQ AX, X0
+	PSHUFD $0, X0, X0
+	MOVOU (4*16)(SP), X1
+	PXOR X1, X0
+	MOVOU (12*16)(SP), X1
+	PXOR X1, X0
+	MOVOU X0, (4*16)(DI)
+	MOVL (5*4)(DX), AX
+	MOVQ AX, X0
+	PSHUFD $0, X0, X0
+	MOVOU (5*16)(SP), X1
+	PXOR X1, X0
+	MOVOU (13*16)(SP), X1
+	PXOR X1, X0
+	MOVOU X0, (5*16)(DI)
+	MOVL (6*4)(DX), AX
+	MOVQ AX, X0
+	PSHUFD $0, X0, X0
+	MOVOU (6*16)(SP), X1
+	PXOR X1, X0
+	MOVOU (14*16)(SP), X1
+	PXOR X1, X0
+	MOVOU X0, (6*16)(DI)
+	MOVL (7*4)(DX), AX
+	MOVQ AX, X0
+	PSHUFD $0, X0, X0
+	MOVOU (7*16)(SP), X1
+	PXOR X1, X0
+	MOVOU (15*16)(SP), X1
+	PXOR X1, X0
+	MOVOU X0, (7*16)(DI)
+	RET
+
+#define ROTR8(x, n, t) \
+	VPSRLD $n, x, t; \
+	VPSLLD $(32-n), x, x; \
+	VPOR t, x, x
+
+// G8 performs the G function on state words a, b, c and d of all eight lanes
+// using message words x and y.
+#define G8(a, b, c, d, x, y) \
+	VMOVDQU (a*32)(SP), Y0; \
+	VMOVDQU (b*32)(SP), Y1; \
+	VMOVDQU (c*32)(SP), Y2; \
+	VMOVDQU (d*32)(SP), Y3; \
+	VPBROADCASTD (y*16)(BX), Y5; \
+	VPXOR (x*32)(SI), Y5, Y4; \
+	VPADDD Y4, Y0, Y0; \
+	VPADDD Y1, Y0, Y0; \
+	VPXOR Y0, Y3, Y3; \
+	VPSHUFB Y14, Y3, Y3; \
+	VPADDD Y3, Y2, Y2; \
+	VPXOR Y2, Y1, Y1; \
+	ROTR8(Y1, 12, Y4); \
+	VPBROADCASTD (x*16)(BX), Y5; \
+	VPXOR (y*32)(SI), Y5, Y4; \
+	VPADDD Y4, Y0, Y0; \
+	VPADDD Y1, Y0, Y0; \
+	VPXOR Y0, Y3, Y3; \
+	VPSHUFB Y15, Y3, Y3; \
+	VPADDD Y3, Y2, Y2; \
+	VPXOR Y2, Y1, Y1; \
+	ROTR8(Y1, 7, Y4); \
+	VMOVDQU Y0, (a*32)(SP); \
+	VMOVDQU Y1, (b*32)(SP); \
+	VMOVDQU Y2, (c*32)(SP); \
+	VMOVDQU Y3, (d*32)(SP)
+
+// func block8AVX2(out *[8][8]uint32, h *[8]uint32, msg *[16][8]uint32, t uint64)
+TEXT ·block8AVX2(SB), NOSPLIT, $512-32
+	MOVQ out+0(FP), DI
+	MOVQ h+8(FP), DX
+	MOVQ msg+16(FP), SI
+	LEAQ cst<>(SB), BX
+	VBROADCASTI128 rot16<>(SB), Y14
+	VBROADCASTI128 rot8<>(SB), Y15
+
+	// v0..v7 = h
+	VPBROADCASTD (0*4)(DX), Y0
+	VMOVDQU Y0, (0*32)(SP)
+	VPBROADCASTD (1*4)(DX), Y0
+	VMOVDQU Y0, (1*32)(SP)
+	VPBROADCASTD (2*4)(DX), Y0
+	VMOVDQU Y0, (2*32)(SP)
+	VPBROADCASTD (3*4)(DX), Y0
+	VMOVDQU Y0, (3*32)(SP)
+	VPBROADCASTD (4*4)(DX), Y0
+	VMOVDQU Y0, (4*32)(SP)
+	VPBROADCASTD (5*4)(DX), Y0
+	VMOVDQU Y0, (5*32)(SP)
+	VPBROADCASTD (6*4)(DX), Y0
+	VMOVDQU Y0, (6*32)(SP)
+	VPBROADCASTD (7*4)(DX), Y0
+	VMOVDQU Y0, (7*32)(SP)
+
+	// v8..v11 = cst0..cst3
+	VPBROADCASTD (0*16)(BX), Y0
+	VMOVDQU Y0, (8*32)(SP)
+	VPBROADCASTD (1*16)(BX), Y0
+	VMOVDQU Y0, (9*32)(SP)
+	VPBROADCASTD (2*16)(BX), Y0
+	VMOVDQU Y0, (10*32)(SP)
+	VPBROADCASTD (3*16)(BX), Y0
+	VMOVDQU Y0, (11*32)(SP)
+
+	// v12..v15 = cst4..cst7 xor the counter.
+	MOVQ t+24(FP), AX
+	MOVQ AX, X7
+	VPBROADCASTD X7, Y7
+	SHRQ $32, AX
+	MOVQ AX, X6
+	VPBROADCASTD X6, Y6
+	VPBROADCASTD (4*16)(BX), Y0
+	VPXOR Y7, Y0, Y0
+	VMOVDQU Y0, (12*32)(SP)
+	VPBROADCASTD (5*16)(BX), Y0
+	VPXOR Y7, Y0, Y0
+	VMOVDQU Y0, (13*32)(SP)
+	VPBROADCASTD (6*16)(BX), Y0
+	VPXOR Y6, Y0, Y0
+	VMOVDQU Y0, (14*32)(SP)
+	VPBROADCASTD (7*16)(BX), Y0
+	VPXOR Y6, Y0, Y0
+	VMOVDQU Y0, (15*32)(SP)
+
+	// Round 1.
+	G8(0, 4, 8, 12, 0, 1)
+	G8(1, 5, 9, 13, 2, 3)
+	G8(2, 6, 10, 14, 4, 5)
+	G8(3, 7, 11, 15, 6, 7)
+	G8(0, 5, 10, 15, 8, 9)
+	G8(1, 6, 11, 12, 10, 11)
+	G8(2, 7, 8, 13, 12, 13)
+	G8(3, 4, 9, 14, 14, 15)
+	// Round 2.
+	G8(0, 4, 8, 12, 14, 10)
+	G8(1, 5, 9, 13, 4, 8)
+	G8(2, 6, 10, 14, 9, 15)
+	G8(3, 7, 11, 15, 13, 6)
+	G8(0, 5, 10, 15, 1, 12)
+	G8(1, 6, 11, 12, 0, 2)
+	G8(2, 7, 8, 13, 11, 7)
+	G8(3, 4, 9, 14, 5, 3)
+	// Round 3.
+	G8(0, 4, 8, 12, 11, 8)
+	G8(1, 5, 9, 13, 12, 0)
+	G8(2, 6, 10, 14, 5, 2)
+	G8(3, 7, 11, 15, 15, 13)
+	G8(0, 5, 10, 15, 10, 14)
+	G8(1, 6, 11, 12, 3, 6)
+	G8(2, 7, 8, 13, 7, 1)
+	G8(3, 4, 9, 14, 9, 4)
+	// Round 4.
+	G8(0, 4, 8, 12, 7, 9)
+	G8(1, 5, 9, 13, 3, 1)
+	G8(2, 6, 10, 14, 13, 12)
+	G8(3, 7, 11, 15, 11, 14)
+	G8(0, 5, 10, 15, 2, 6)
+	G8(1, 6, 11, 12, 5, 10)
+	G8(2, 7, 8, 13, 4, 0)
+	G8(3, 4, 9, 14, 15, 8)
+	// Round 5.
+	G8(0, 4, 8, 12, 9, 0)
+	G8(1, 5, 9, 13, 5, 7)
+	G8(2, 6, 10, 14, 2, 4)
+	G8(3, 7, 11, 15, 10, 15)
+	G8(0, 5, 10, 15, 14, 1)
+	G8(1, 6, 11, 12, 11, 12)
+	G8(2, 7, 8, 13, 6, 8)
+	G8(3, 4, 9, 14, 3, 13)
+	// Round 6.
+	G8(0, 4, 8, 12, 2, 12)
+	G8(1, 5, 9, 13, 6, 10)
+	G8(2, 6, 10, 14, 0, 11)
+	G8(3, 7, 11, 15, 8, 3)
+	G8(0, 5, 10, 15, 4, 13)
+	G8(1, 6, 11, 12, 7, 5)
+	G8(2, 7, 8, 13, 15, 14)
+	G8(3, 4, 9, 14, 1, 9)
+	// Round 7.
+	G8(0, 4, 8, 12, 12, 5)
+	G8(1, 5, 9, 13, 1, 15)
+	G8(2, 6, 10, 14, 14, 13)
+	G8(3, 7, 11, 15, 4, 10)
+	G8(0, 5, 10, 15, 0, 7)
+	G8(1, 6, 11, 12, 6, 3)
+	G8(2, 7, 8, 13, 9, 2)
+	G8(3, 4, 9, 14, 8, 11)
+	// Round 8.
+	G8(0, 4, 8, 12, 13, 11)
+	G8(1, 5, 9, 13, 7, 14)
+	G8(2, 6, 10, 14, 12, 1)
+	G8(3, 7, 11, 15, 3, 9)
+	G8(0, 5, 10, 15, 5, 0)
+	G8(1, 6, 11, 12, 15, 4)
+	G8(2, 7, 8, 13, 8, 6)
+	G8(3, 4, 9, 14, 2, 10)
+	// Round 9.
+	G8(0, 4, 8, 12, 6, 15)
+	G8(1, 5, 9, 13, 14, 9)
+	G8(2, 6, 10, 14, 11, 3)
+	G8(3, 7, 11, 15, 0, 8)
+	G8(0, 5, 10, 15, 12, 2)
+	G8(1, 6, 11, 12, 13, 7)
+	G8(2, 7, 8, 13, 1, 4)
+	G8(3, 4, 9, 14, 10, 5)
+	// Round 10.
+	G8(0, 4, 8, 12, 10, 2)
+	G8(1, 5, 9, 13, 8, 4)
+	G8(2, 6, 10, 14, 7, 6)
+	G8(3, 7, 11, 15, 1, 5)
+	G8(0, 5, 10, 15, 15, 11)
+	G8(1, 6, 11, 12, 9, 14)
+	G8(2, 7, 8, 13, 3, 12)
+	G8(3, 4, 9, 14, 13, 0)
+	// Round 11.
+	G8(0, 4, 8, 12, 0, 1)
+	G8(1, 5, 9, 13, 2, 3)
+	G8(2, 6, 10, 14, 4, 5)
+	G8(3, 7, 11, 15, 6, 7)
+	G8(0, 5, 10, 15, 8, 9)
+	G8(1, 6, 11, 12, 10, 11)
+	G8(2, 7, 8, 13, 12, 13)
+	G8(3, 4, 9, 14, 14, 15)
+	// Round 12.
+	G8(0, 4, 8, 12, 14, 10)
+	G8(1, 5, 9, 13, 4, 8)
+	G8(2, 6, 10, 14, 9, 15)
+	G8(3, 7, 11, 15, 13, 6)
+	G8(0, 5, 10, 15, 1, 12)
+	G8(1, 6, 11, 12, 0, 2)
+	G8(2, 7, 8, 13, 11, 7)
+	G8(3, 4, 9, 14, 5, 3)
+	// Round 13.
+	G8(0, 4, 8, 12, 11, 8)
+	G8(1, 5, 9, 13, 12, 0)
+	G8(2, 6, 10, 14, 5, 2)
+	G8(3, 7, 11, 15, 15, 13)
+	G8(0, 5, 10, 15, 10, 14)
+	G8(1, 6, 11, 12, 3, 6)
+	G8(2, 7, 8, 13, 7, 1)
+	G8(3, 4, 9, 14, 9, 4)
+	// Round 14.
+	G8(0, 4, 8, 12, 7, 9)
+	G8(1, 5, 9, 13, 3, 1)
+	G8(2, 6, 10, 14, 13, 12)
+	G8(3, 7, 11, 15, 11, 14)
+	G8(0, 5, 10, 15, 2, 6)
+	G8(1, 6, 11, 12, 5, 10)
+	G8(2, 7, 8, 13, 4, 0)
+	G8(3, 4, 9, 14, 15, 8)
+
+	// out = h ^ v0..v7 ^ v8..v15
+	VPBROADCASTD (0*4)(DX), Y0
+	VPXOR (0*32)(SP), Y0, Y0
+	VPXOR (8*32)(SP), Y0, Y0
+	VMOVDQU Y0, (0*32)(DI)
+	VPBROADCASTD (1*4)(DX), Y0
+	VPXOR (1*32)(SP), Y0, Y0
+	VPXOR (9*32)(SP), Y0, Y0
+	VMOVDQU Y0, (1*32)(DI)
+	VPBROADCASTD (2*4)(DX), Y0
+	VPXOR (2*32)(SP), Y0, Y0
+	VPXOR (10*32)(SP), Y0, Y0
+	VMOVDQU Y0, (2*32)(DI)
+	VPBROADCASTD (3*4)(DX), Y0
+	VPXOR (3*32)(SP), Y0, Y0
+	VPXOR (11*32)(SP), Y0, Y0
+	VMOVDQU Y0, (3*32)(DI)
+	VPBROADCASTD (4*4)(DX), Y0
+	VPXOR (4*32)(SP), Y0, Y0
+	VPXOR (12*32)(SP), Y0, Y0
+	VMOVDQU Y0, (4*32)(DI)
+	VPBROADCASTD (5*4)(DX), Y0
+	VPXOR (5*32)(SP), Y0, Y0
+	VPXOR (13*32)(SP), Y0, Y0
+	VMOVDQU Y0, (5*32)(DI)
+	VPBROADCASTD (6*4)(DX), Y0
+	VPXOR (6*32)(SP), Y0, Y0
+	VPXOR (14*32)(SP), Y0, Y0
+	VMOVDQU Y0, (6*32)(DI)
+	VPBROADCASTD (7*4)(DX), Y0
+	VPXOR (7*32)(SP), Y0, Y0
+	VPXOR (15*32)(SP), Y0, Y0
+	VMOVDQU Y0, (7*32)(DI)
+	VZEROUPPER
+	RET
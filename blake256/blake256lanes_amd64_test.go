@@ -0,0 +1,35 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blake256
+
+// lanePath is an implementation of the multi-lane block functions.
+type lanePath struct {
+	name      string
+	supported bool
+
+	// use selects the implementation and returns a function restoring
+	// the one selected before.
+	use func() (restore func())
+}
+
+// lanePaths returns the AVX2, SSSE3 and generic implementations, selected by
+// turning off the faster ones in turn.
+func lanePaths() []lanePath {
+	hasSSSE3, hasAVX2 := useSSSE3, useAVX2
+	selectPath := func(ssse3, avx2 bool) func() func() {
+		return func() func() {
+			savedSSSE3, savedAVX2 := useSSSE3, useAVX2
+			useSSSE3, useAVX2 = ssse3, avx2
+			return func() {
+				useSSSE3, useAVX2 = savedSSSE3, savedAVX2
+			}
+		}
+	}
+	return []lanePath{
+		{"avx2", hasAVX2, selectPath(hasSSSE3, true)},
+		{"ssse3", hasSSSE3, selectPath(true, false)},
+		{"generic", true, selectPath(false, false)},
+	}
+}
@@ -0,0 +1,16 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+//go:build !amd64
+// +build !amd64
+
+package blake256
+
+func block4(out *[8][4]uint32, h *[8]uint32, m *[16]uint32, nonceWord int, nonces *[4]uint32, t uint64) {
+	block4Generic(out, h, m, nonceWord, nonces, t)
+}
+
+func block8(out *[8][8]uint32, h *[8]uint32, m *[16]uint32, nonceWord int, nonces *[8]uint32, t uint64) {
+	block8Generic(out, h, m, nonceWord, nonces, t)
+}
@@ -0,0 +1,25 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+//go:build !amd64
+// +build !amd64
+
+package blake256
+
+// lanePath is an implementation of the multi-lane block functions.
+type lanePath struct {
+	name      string
+	supported bool
+
+	// use selects the implementation and returns a function restoring
+	// the one selected before.
+	use func() (restore func())
+}
+
+// lanePaths returns the generic implementation, which is the only one.
+func lanePaths() []lanePath {
+	return []lanePath{
+		{"generic", true, func() func() { return func() {} }},
+	}
+}
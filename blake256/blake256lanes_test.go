@@ -0,0 +1,83 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blake256
+
+import (
+	"encoding/binary"
+	"math/rand"
+	"testing"
+)
+
+// laneBlock hashes a single lane with Block, which is what every lane of
+// Block4 and Block8 must match.
+func laneBlock(h *[8]uint32, m *[16]uint32, nonceWord int, nonce uint32, t uint64) [8]uint32 {
+	var p [64]byte
+	for i, w := range m {
+		binary.BigEndian.PutUint32(p[i*4:], w)
+	}
+	binary.BigEndian.PutUint32(p[nonceWord*4:], nonce)
+	state := *h
+	Block(state[:], p[:], t)
+	return state
+}
+
+// TestBlockLanes checks that every lane of Block4 and Block8 is exactly the
+// result of Block on every implementation the cpu supports.
+func TestBlockLanes(t *testing.T) {
+	for _, path := range lanePaths() {
+		if !path.supported {
+			t.Logf("%s: not supported by the cpu, skipping", path.name)
+			continue
+		}
+		restore := path.use()
+
+		r := rand.New(rand.NewSource(1))
+		for i := 0; i < 500; i++ {
+			var h [8]uint32
+			var m [16]uint32
+			var nonces [8]uint32
+			for j := range h {
+				h[j] = r.Uint32()
+			}
+			for j := range m {
+				m[j] = r.Uint32()
+			}
+			for j := range nonces {
+				nonces[j] = r.Uint32()
+			}
+			nonceWord := r.Intn(16)
+			counter := r.Uint64()
+			if i%2 == 0 {
+				// The counter of 180 byte headers.
+				counter = 1440
+			}
+
+			var out8 [8][8]uint32
+			Block8(&out8, &h, &m, nonceWord, &nonces, counter)
+			var out4 [8][4]uint32
+			var nonces4 [4]uint32
+			copy(nonces4[:], nonces[:4])
+			Block4(&out4, &h, &m, nonceWord, &nonces4, counter)
+
+			for lane, nonce := range nonces {
+				want := laneBlock(&h, &m, nonceWord, nonce, counter)
+				for j := range want {
+					if out8[j][lane] != want[j] {
+						t.Fatalf("%s: Block8 lane %d word %d: "+
+							"got %08x, want %08x", path.name,
+							lane, j, out8[j][lane], want[j])
+					}
+					if lane < 4 && out4[j][lane] != want[j] {
+						t.Fatalf("%s: Block4 lane %d word %d: "+
+							"got %08x, want %08x", path.name,
+							lane, j, out4[j][lane], want[j])
+					}
+				}
+			}
+		}
+
+		restore()
+	}
+}
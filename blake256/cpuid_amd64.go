@@ -0,0 +1,41 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blake256
+
+// cpuid executes the CPUID instruction with the given leaf and subleaf.
+func cpuid(eaxArg, ecxArg uint32) (eax, ebx, ecx, edx uint32)
+
+// xgetbv returns the low and high words of extended control register 0.
+func xgetbv() (eax, edx uint32)
+
+// hasSSSE3 reports whether the cpu supports the SSSE3 instructions.
+func hasSSSE3() bool {
+	_, _, ecx, _ := cpuid(1, 0)
+	return ecx&(1<<9) != 0
+}
+
+// hasAVX2 reports whether both the cpu and the operating system support the
+// AVX2 instructions.
+func hasAVX2() bool {
+	maxLeaf, _, _, _ := cpuid(0, 0)
+	if maxLeaf < 7 {
+		return false
+	}
+
+	// The OS must have enabled XSAVE and saving of the XMM and YMM
+	// registers for AVX to be usable.
+	_, _, ecx, _ := cpuid(1, 0)
+	const osxsave, avx = 1 << 27, 1 << 28
+	if ecx&osxsave == 0 || ecx&avx == 0 {
+		return false
+	}
+	xcr0, _ := xgetbv()
+	if xcr0&6 != 6 {
+		return false
+	}
+
+	_, ebx, _, _ := cpuid(7, 0)
+	return ebx&(1<<5) != 0
+}
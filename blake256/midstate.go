@@ -0,0 +1,224 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blake256
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash"
+)
+
+const (
+	midstateMagic256 = "b256\x01"
+	midstateMagic224 = "b224\x01"
+
+	// MidstateSize is the size of a marshaled Midstate in bytes.
+	MidstateSize = len(midstateMagic256) + 8*4 + SaltSize + 1 + 8 + 1 +
+		BlockSize
+)
+
+var (
+	// ErrMidstateFormat is returned when unmarshaling data that is not a
+	// marshaled midstate.
+	ErrMidstateFormat = errors.New("blake256: invalid midstate format")
+
+	// ErrMidstateSize is returned when unmarshaling data of the wrong
+	// length.
+	ErrMidstateSize = errors.New("blake256: invalid midstate size")
+
+	// ErrNotBlake256 is returned by ExportMidstate for a hash.Hash not
+	// created by this package.
+	ErrNotBlake256 = errors.New("blake256: hash was not created by " +
+		"this package")
+)
+
+// Midstate is the state of a partially computed BLAKE-256 or BLAKE-224
+// checksum.  It can be moved between processes with MarshalBinary and
+// UnmarshalBinary, and hashing continues from it with Hash.
+//
+// The miner uses it to hash the first two blocks of a header once per work
+// unit and only hash the final block for every nonce.
+type Midstate struct {
+	// H is the chaining value.
+	H [8]uint32
+
+	// Salt is the salt as uint32 words, all zero for standard BLAKE-256.
+	Salt [4]uint32
+
+	// Rounds is the number of rounds per block.  Zero means Rounds.
+	Rounds int
+
+	// T is the number of message bits compressed into H.  It is always a
+	// multiple of the block size in bits.
+	T uint64
+
+	// Buf holds the message bytes written after the last compressed
+	// block.  It is always shorter than BlockSize.
+	Buf []byte
+
+	// Is224 is set for a BLAKE-224 state.
+	Is224 bool
+}
+
+// NewMidstate returns the BLAKE-256 midstate after writing data.
+func NewMidstate(data []byte) *Midstate {
+	d := digest{size: Size, rounds: Rounds}
+	d.Reset()
+	d.Write(data)
+	return d.midstate()
+}
+
+// ExportMidstate returns the midstate of a hash.Hash returned by New or
+// New224.  The hash itself is not modified.
+func ExportMidstate(h hash.Hash) (*Midstate, error) {
+	d, ok := h.(*digest)
+	if !ok {
+		return nil, ErrNotBlake256
+	}
+	return d.midstate(), nil
+}
+
+// valid returns whether the fields of the midstate are within their ranges.
+func (m *Midstate) valid() bool {
+	return len(m.Buf) < BlockSize && m.T%(BlockSize*8) == 0 &&
+		m.Rounds >= 0 && m.Rounds <= Rounds
+}
+
+// Hash returns a new hash.Hash that continues from the midstate.  Writing the
+// rest of the message to it gives the same checksum as writing the whole
+// message to a fresh hash.  It returns ErrMidstateFormat when a field of the
+// midstate is out of range.
+func (m *Midstate) Hash() (hash.Hash, error) {
+	if !m.valid() {
+		return nil, ErrMidstateFormat
+	}
+	d := &digest{size: Size, rounds: m.Rounds}
+	if m.Is224 {
+		d.size = Size224
+	}
+	if d.rounds == 0 {
+		d.rounds = Rounds
+	}
+	d.h = m.H
+	d.s = m.Salt
+	d.t = m.T
+	d.nx = copy(d.x[:], m.Buf)
+	return d, nil
+}
+
+// MarshalBinary encodes the midstate.  It implements the
+// encoding.BinaryMarshaler interface.
+func (m *Midstate) MarshalBinary() ([]byte, error) {
+	if !m.valid() {
+		return nil, ErrMidstateFormat
+	}
+	rounds := m.Rounds
+	if rounds == 0 {
+		rounds = Rounds
+	}
+
+	b := make([]byte, 0, MidstateSize)
+	if m.Is224 {
+		b = append(b, midstateMagic224...)
+	} else {
+		b = append(b, midstateMagic256...)
+	}
+	var buf [8]byte
+	for _, v := range m.H {
+		binary.BigEndian.PutUint32(buf[:4], v)
+		b = append(b, buf[:4]...)
+	}
+	for _, v := range m.Salt {
+		binary.BigEndian.PutUint32(buf[:4], v)
+		b = append(b, buf[:4]...)
+	}
+	b = append(b, byte(rounds))
+	binary.BigEndian.PutUint64(buf[:], m.T)
+	b = append(b, buf[:]...)
+	b = append(b, byte(len(m.Buf)))
+	b = append(b, m.Buf...)
+	b = append(b, make([]byte, BlockSize-len(m.Buf))...)
+	return b, nil
+}
+
+// UnmarshalBinary decodes a midstate encoded by MarshalBinary.  It implements
+// the encoding.BinaryUnmarshaler interface.
+func (m *Midstate) UnmarshalBinary(b []byte) error {
+	if len(b) < len(midstateMagic256) {
+		return ErrMidstateFormat
+	}
+	var is224 bool
+	switch string(b[:len(midstateMagic256)]) {
+	case midstateMagic256:
+	case midstateMagic224:
+		is224 = true
+	default:
+		return ErrMidstateFormat
+	}
+	if len(b) != MidstateSize {
+		return ErrMidstateSize
+	}
+
+	b = b[len(midstateMagic256):]
+	var h [8]uint32
+	for i := range h {
+		h[i] = binary.BigEndian.Uint32(b[i*4:])
+	}
+	b = b[8*4:]
+	var salt [4]uint32
+	for i := range salt {
+		salt[i] = binary.BigEndian.Uint32(b[i*4:])
+	}
+	b = b[SaltSize:]
+	rounds := int(b[0])
+	b = b[1:]
+	t := binary.BigEndian.Uint64(b)
+	nx := int(b[8])
+	if nx >= BlockSize || t%(BlockSize*8) != 0 || rounds < 1 ||
+		rounds > Rounds {
+		return ErrMidstateFormat
+	}
+
+	m.H = h
+	m.Salt = salt
+	m.Rounds = rounds
+	m.T = t
+	m.Buf = append(m.Buf[:0], b[9:9+nx]...)
+	m.Is224 = is224
+	return nil
+}
+
+// midstate returns a copy of the digest state.
+func (d *digest) midstate() *Midstate {
+	return &Midstate{
+		H:      d.h,
+		Salt:   d.s,
+		Rounds: d.rounds,
+		T:      d.t,
+		Buf:    append([]byte(nil), d.x[:d.nx]...),
+		Is224:  d.size == Size224,
+	}
+}
+
+// MarshalBinary encodes the hash state in the same format as
+// Midstate.MarshalBinary.
+func (d *digest) MarshalBinary() ([]byte, error) {
+	return d.midstate().MarshalBinary()
+}
+
+// UnmarshalBinary restores a hash state encoded by MarshalBinary, including
+// its size.
+func (d *digest) UnmarshalBinary(b []byte) error {
+	var m Midstate
+	if err := m.UnmarshalBinary(b); err != nil {
+		return err
+	}
+	h, err := m.Hash()
+	if err != nil {
+		return err
+	}
+	*d = *h.(*digest)
+	return nil
+}
@@ -0,0 +1,161 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blake256
+
+import (
+	"bytes"
+	"encoding"
+	"hash"
+	"testing"
+)
+
+// TestMidstateRoundTrip checks that hashing continues from a marshaled and
+// unmarshaled midstate as if the whole message had been written at once.
+func TestMidstateRoundTrip(t *testing.T) {
+	salt := []byte("0123456789abcdef")
+	newHashes := []struct {
+		name string
+		new  func() hash.Hash
+	}{
+		{"New", New},
+		{"New224", New224},
+		{"NewSalted", func() hash.Hash {
+			h, _ := NewSalted(salt, 8)
+			return h
+		}},
+		{"NewSalted224", func() hash.Hash {
+			h, _ := NewSalted224(salt, Rounds)
+			return h
+		}},
+	}
+
+	msg := make([]byte, 180)
+	for i := range msg {
+		msg[i] = byte(i)
+	}
+
+	for _, nh := range newHashes {
+		whole := nh.new()
+		whole.Write(msg)
+		want := whole.Sum(nil)
+
+		for _, split := range []int{0, 1, 63, 64, 65, 128, 179, 180} {
+			h := nh.new()
+			h.Write(msg[:split])
+			m, err := ExportMidstate(h)
+			if err != nil {
+				t.Fatalf("%s: ExportMidstate: %v", nh.name, err)
+			}
+			b, err := m.MarshalBinary()
+			if err != nil {
+				t.Fatalf("%s: MarshalBinary: %v", nh.name, err)
+			}
+			if len(b) != MidstateSize {
+				t.Fatalf("%s: marshaled %d bytes, want %d",
+					nh.name, len(b), MidstateSize)
+			}
+
+			var m2 Midstate
+			err = m2.UnmarshalBinary(b)
+			if err != nil {
+				t.Fatalf("%s: UnmarshalBinary: %v", nh.name, err)
+			}
+			h2, err := m2.Hash()
+			if err != nil {
+				t.Fatalf("%s: Hash: %v", nh.name, err)
+			}
+			h2.Write(msg[split:])
+			if got := h2.Sum(nil); !bytes.Equal(got, want) {
+				t.Errorf("%s split at %d: got %x, want %x",
+					nh.name, split, got, want)
+			}
+
+			// The hash itself marshals to the same format.
+			hb, err := h.(encoding.BinaryMarshaler).MarshalBinary()
+			if err != nil || !bytes.Equal(hb, b) {
+				t.Fatalf("%s: hash MarshalBinary: %x, %v",
+					nh.name, hb, err)
+			}
+			h3 := New()
+			err = h3.(encoding.BinaryUnmarshaler).UnmarshalBinary(b)
+			if err != nil {
+				t.Fatalf("%s: hash UnmarshalBinary: %v", nh.name,
+					err)
+			}
+			h3.Write(msg[split:])
+			if got := h3.Sum(nil); !bytes.Equal(got, want) {
+				t.Errorf("%s split at %d, hash restored: got %x, "+
+					"want %x", nh.name, split, got, want)
+			}
+		}
+	}
+
+	// NewMidstate is the midstate of New.
+	m := NewMidstate(msg[:128])
+	h, err := m.Hash()
+	if err != nil {
+		t.Fatalf("NewMidstate: Hash: %v", err)
+	}
+	h.Write(msg[128:])
+	want := Sum256(msg)
+	if got := h.Sum(nil); !bytes.Equal(got, want[:]) {
+		t.Errorf("NewMidstate: got %x, want %x", got, want)
+	}
+}
+
+// TestMidstateInvalid checks that out of range midstates are rejected
+// instead of being truncated.
+func TestMidstateInvalid(t *testing.T) {
+	valid := NewMidstate(make([]byte, 100))
+
+	tests := []struct {
+		name   string
+		modify func(m *Midstate)
+	}{
+		{"oversized buffer", func(m *Midstate) {
+			m.Buf = make([]byte, BlockSize)
+		}},
+		{"negative rounds", func(m *Midstate) { m.Rounds = -1 }},
+		{"too many rounds", func(m *Midstate) { m.Rounds = Rounds + 1 }},
+		{"partial block counter", func(m *Midstate) { m.T += 8 }},
+	}
+	for _, test := range tests {
+		m := *valid
+		test.modify(&m)
+		if _, err := m.Hash(); err != ErrMidstateFormat {
+			t.Errorf("%s: Hash returned %v, want %v", test.name, err,
+				ErrMidstateFormat)
+		}
+		if _, err := m.MarshalBinary(); err != ErrMidstateFormat {
+			t.Errorf("%s: MarshalBinary returned %v, want %v",
+				test.name, err, ErrMidstateFormat)
+		}
+	}
+
+	b, err := valid.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+	var m Midstate
+	if err := m.UnmarshalBinary(b[:len(b)-1]); err != ErrMidstateSize {
+		t.Errorf("short data: got %v, want %v", err, ErrMidstateSize)
+	}
+	bad := append([]byte(nil), b...)
+	bad[0] ^= 0xff
+	if err := m.UnmarshalBinary(bad); err != ErrMidstateFormat {
+		t.Errorf("bad magic: got %v, want %v", err, ErrMidstateFormat)
+	}
+	bad = append([]byte(nil), b...)
+	bad[len(midstateMagic256)+8*4+SaltSize] = Rounds + 1
+	if err := m.UnmarshalBinary(bad); err != ErrMidstateFormat {
+		t.Errorf("bad rounds: got %v, want %v", err, ErrMidstateFormat)
+	}
+	bad = append([]byte(nil), b...)
+	bad[len(midstateMagic256)+8*4+SaltSize+1+8] = BlockSize
+	if err := m.UnmarshalBinary(bad); err != ErrMidstateFormat {
+		t.Errorf("bad buffer length: got %v, want %v", err,
+			ErrMidstateFormat)
+	}
+}
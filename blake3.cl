@@ -0,0 +1,153 @@
+/**
+ * BLAKE3 kernel for the Decred proof of work (DCP-0011)
+ *
+ * Only the final block of the 180 byte header is hashed.  The chaining value
+ * after the first two blocks is passed in as the midstate and the final block
+ * holds the last 52 header bytes, so it is compressed with a block length of
+ * 52 and the CHUNK_END and ROOT flags.  Message words are little endian.
+ */
+#define ROTR(v,n) rotate(v,(uint)(32U-n))
+
+#define G(a,b,c,d,x,y) \
+	a = a + b + x; d = ROTR(d ^ a, 16U); c = c + d; b = ROTR(b ^ c, 12U); \
+	a = a + b + y; d = ROTR(d ^ a, 8U); c = c + d; b = ROTR(b ^ c, 7U);
+
+__attribute__((reqd_work_group_size(WORKSIZE, 1, 1)))
+__kernel void search(
+	volatile __global uint * restrict output,
+	// Midstate
+	const uint h0,
+	const uint h1,
+	const uint h2,
+	const uint h3,
+	const uint h4,
+	const uint h5,
+	const uint h6,
+	const uint h7,
+
+	// last 52 bytes of data
+	const uint M0,
+	const uint M1,
+	const uint M2,
+	// const uint M3 : nonce
+	const uint M4,
+	const uint M5,
+	const uint M6,
+	const uint M7,
+	const uint M8,
+	const uint M9,
+	const uint MA,
+	const uint MB,
+	const uint MC
+)
+{
+	/* Load the block header and padding */
+	const uint M3 = get_global_id(0);
+	const uint MD = 0;
+	const uint ME = 0;
+	const uint MF = 0;
+
+	uint V0, V1, V2, V3, V4, V5, V6, V7;
+	uint V8, V9, VA, VB, VC, VD, VE, VF;
+
+	/* Load the midstate and initialize */
+	V0 = h0;
+	V1 = h1;
+	V2 = h2;
+	V3 = h3;
+	V4 = h4;
+	V5 = h5;
+	V6 = h6;
+	V7 = h7;
+
+	V8 = 0x6A09E667UL;
+	V9 = 0xBB67AE85UL;
+	VA = 0x3C6EF372UL;
+	VB = 0xA54FF53AUL;
+	VC = 0;     /* counter low */
+	VD = 0;     /* counter high */
+	VE = 52;    /* block length */
+	VF = 0x0A;  /* CHUNK_END | ROOT */
+
+	/* 7 rounds */
+
+	/* Round 1 */
+	G(V0, V4, V8, VC, M0, M1);
+	G(V1, V5, V9, VD, M2, M3);
+	G(V2, V6, VA, VE, M4, M5);
+	G(V3, V7, VB, VF, M6, M7);
+	G(V0, V5, VA, VF, M8, M9);
+	G(V1, V6, VB, VC, MA, MB);
+	G(V2, V7, V8, VD, MC, MD);
+	G(V3, V4, V9, VE, ME, MF);
+
+	/* Round 2 */
+	G(V0, V4, V8, VC, M2, M6);
+	G(V1, V5, V9, VD, M3, MA);
+	G(V2, V6, VA, VE, M7, M0);
+	G(V3, V7, VB, VF, M4, MD);
+	G(V0, V5, VA, VF, M1, MB);
+	G(V1, V6, VB, VC, MC, M5);
+	G(V2, V7, V8, VD, M9, ME);
+	G(V3, V4, V9, VE, MF, M8);
+
+	/* Round 3 */
+	G(V0, V4, V8, VC, M3, M4);
+	G(V1, V5, V9, VD, MA, MC);
+	G(V2, V6, VA, VE, MD, M2);
+	G(V3, V7, VB, VF, M7, ME);
+	G(V0, V5, VA, VF, M6, M5);
+	G(V1, V6, VB, VC, M9, M0);
+	G(V2, V7, V8, VD, MB, MF);
+	G(V3, V4, V9, VE, M8, M1);
+
+	/* Round 4 */
+	G(V0, V4, V8, VC, MA, M7);
+	G(V1, V5, V9, VD, MC, M9);
+	G(V2, V6, VA, VE, ME, M3);
+	G(V3, V7, VB, VF, MD, MF);
+	G(V0, V5, VA, VF, M4, M0);
+	G(V1, V6, VB, VC, MB, M2);
+	G(V2, V7, V8, VD, M5, M8);
+	G(V3, V4, V9, VE, M1, M6);
+
+	/* Round 5 */
+	G(V0, V4, V8, VC, MC, MD);
+	G(V1, V5, V9, VD, M9, MB);
+	G(V2, V6, VA, VE, MF, MA);
+	G(V3, V7, VB, VF, ME, M8);
+	G(V0, V5, VA, VF, M7, M2);
+	G(V1, V6, VB, VC, M5, M3);
+	G(V2, V7, V8, VD, M0, M1);
+	G(V3, V4, V9, VE, M6, M4);
+
+	/* Round 6 */
+	G(V0, V4, V8, VC, M9, ME);
+	G(V1, V5, V9, VD, MB, M5);
+	G(V2, V6, VA, VE, M8, MC);
+	G(V3, V7, VB, VF, MF, M1);
+	G(V0, V5, VA, VF, MD, M3);
+	G(V1, V6, VB, VC, M0, MA);
+	G(V2, V7, V8, VD, M2, M6);
+	G(V3, V4, V9, VE, M4, M7);
+
+	/* Round 7 */
+	G(V0, V4, V8, VC, MB, MF);
+	G(V1, V5, V9, VD, M5, M0);
+	G(V2, V6, VA, VE, M1, M9);
+	G(V3, V7, VB, VF, M8, M6);
+	G(V0, V5, VA, VF, ME, MA);
+	G(V1, V6, VB, VC, M2, MC);
+	G(V2, V7, V8, VD, M3, M4);
+	G(V3, V4, V9, VE, M7, MD);
+
+	/* The hash is h[i] = V[i] ^ V[i + 8] and is read as a little endian
+	 * number, so the last word holds its most significant bits.  Only
+	 * nonces that zero it are reported and then checked against the
+	 * target on the host.
+	 */
+	if (V7 ^ VF) return;
+
+	/* Push this share */
+	output[++output[0]] = M3;
+}
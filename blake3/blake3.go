@@ -0,0 +1,216 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package blake3 implements the unkeyed BLAKE3 hash function with a 32 byte
+// output, which is the proof-of-work hash of Decred since DCP-0011.
+//
+// Besides the standard hash.Hash interface, the package exports the raw block
+// function used by the miner to hash only the final block of a header.
+package blake3
+
+import (
+	"encoding/binary"
+	"hash"
+)
+
+const (
+	// BlockSize is the block size of BLAKE3 in bytes.
+	BlockSize = 64
+
+	// ChunkSize is the number of bytes hashed into each leaf of the
+	// BLAKE3 tree.
+	ChunkSize = 1024
+
+	// Size is the size of a BLAKE3 checksum in bytes.
+	Size = 32
+
+	// maxDepth is the height of the tree for the largest possible input
+	// of 2^64 bytes.
+	maxDepth = 54
+)
+
+// chunkState is the state of the chunk currently being hashed.
+type chunkState struct {
+	h                [8]uint32
+	counter          uint64
+	block            [BlockSize]byte
+	blockLen         int
+	blocksCompressed int
+}
+
+func (c *chunkState) reset(counter uint64) {
+	c.h = IV
+	c.counter = counter
+	c.blockLen = 0
+	c.blocksCompressed = 0
+}
+
+func (c *chunkState) len() int {
+	return c.blocksCompressed*BlockSize + c.blockLen
+}
+
+func (c *chunkState) startFlag() uint32 {
+	if c.blocksCompressed == 0 {
+		return FlagChunkStart
+	}
+	return 0
+}
+
+// write adds up to the rest of the chunk from p and returns the number of bytes
+// used.  The last block is kept buffered since it needs the chunk end flag.
+func (c *chunkState) write(p []byte) int {
+	nn := 0
+	for len(p) > 0 {
+		if c.blockLen == BlockSize {
+			var m [16]uint32
+			blockWords(&m, c.block[:])
+			Block(&c.h, &m, c.counter, BlockSize, c.startFlag())
+			c.blocksCompressed++
+			c.blockLen = 0
+		}
+		n := copy(c.block[c.blockLen:], p)
+		c.blockLen += n
+		nn += n
+		p = p[n:]
+	}
+	return nn
+}
+
+// output returns the final node of the chunk.
+func (c *chunkState) output() output {
+	o := output{
+		h:        c.h,
+		counter:  c.counter,
+		blockLen: uint32(c.blockLen),
+		flags:    c.startFlag() | FlagChunkEnd,
+	}
+	blockWords(&o.m, c.block[:c.blockLen])
+	return o
+}
+
+// output is a node of the tree whose compression is deferred until it is
+// known whether it is the root.
+type output struct {
+	h        [8]uint32
+	m        [16]uint32
+	counter  uint64
+	blockLen uint32
+	flags    uint32
+}
+
+// chainingValue compresses the node as an inner node.
+func (o *output) chainingValue() [8]uint32 {
+	h := o.h
+	Block(&h, &o.m, o.counter, o.blockLen, o.flags)
+	return h
+}
+
+// parentOutput returns the inner node joining the left and right children.
+func parentOutput(left, right *[8]uint32) output {
+	o := output{
+		h:        IV,
+		blockLen: BlockSize,
+		flags:    FlagParent,
+	}
+	copy(o.m[:8], left[:])
+	copy(o.m[8:], right[:])
+	return o
+}
+
+// digest represents the partial evaluation of a checksum.
+type digest struct {
+	chunk   chunkState
+	stack   [maxDepth][8]uint32 // chaining values of completed subtrees
+	stackLn int
+}
+
+// New returns a new hash.Hash computing the BLAKE3 checksum.
+func New() hash.Hash {
+	d := new(digest)
+	d.Reset()
+	return d
+}
+
+// Reset resets the hash to its initial state.
+func (d *digest) Reset() {
+	d.chunk.reset(0)
+	d.stackLn = 0
+}
+
+// Size returns the number of bytes Sum will return.
+func (d *digest) Size() int {
+	return Size
+}
+
+// BlockSize returns the hash's underlying block size.
+func (d *digest) BlockSize() int {
+	return BlockSize
+}
+
+// addChunk pushes the chaining value of a completed chunk, first merging it
+// with every completed subtree of the same size.  The number of trailing zero
+// bits of the total number of chunks is the number of merges.
+func (d *digest) addChunk(h [8]uint32, totalChunks uint64) {
+	for totalChunks&1 == 0 {
+		d.stackLn--
+		p := parentOutput(&d.stack[d.stackLn], &h)
+		h = p.chainingValue()
+		totalChunks >>= 1
+	}
+	d.stack[d.stackLn] = h
+	d.stackLn++
+}
+
+// Write adds more data to the running hash.  It never returns an error.
+func (d *digest) Write(p []byte) (int, error) {
+	nn := len(p)
+	for len(p) > 0 {
+		// A full chunk is only finished once more data arrives since
+		// the last chunk must be kept for the root.
+		if d.chunk.len() == ChunkSize {
+			o := d.chunk.output()
+			total := d.chunk.counter + 1
+			d.addChunk(o.chainingValue(), total)
+			d.chunk.reset(total)
+		}
+		want := ChunkSize - d.chunk.len()
+		if want > len(p) {
+			want = len(p)
+		}
+		p = p[d.chunk.write(p[:want]):]
+	}
+	return nn, nil
+}
+
+// Sum appends the current hash to in and returns the resulting slice.  It does
+// not change the underlying hash state.
+func (d *digest) Sum(in []byte) []byte {
+	sum := d.checkSum()
+	return append(in, sum[:]...)
+}
+
+// checkSum merges the open subtrees into the root and compresses it.
+func (d *digest) checkSum() [Size]byte {
+	o := d.chunk.output()
+	for i := d.stackLn - 1; i >= 0; i-- {
+		h := o.chainingValue()
+		o = parentOutput(&d.stack[i], &h)
+	}
+
+	h := o.h
+	Block(&h, &o.m, o.counter, o.blockLen, o.flags|FlagRoot)
+	var sum [Size]byte
+	for i, v := range h {
+		binary.LittleEndian.PutUint32(sum[i*4:], v)
+	}
+	return sum
+}
+
+// Sum256 returns the BLAKE3 checksum of the data.
+func Sum256(data []byte) [Size]byte {
+	var d digest
+	d.Reset()
+	d.Write(data)
+	return d.checkSum()
+}
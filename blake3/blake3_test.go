@@ -0,0 +1,94 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blake3
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// hashVectors are the first 32 bytes of the unkeyed checksums in
+// test_vectors.json of the BLAKE3 reference implementation.  The input of
+// each is the repeating sequence 0, 1, ..., 250 of the given length.
+var hashVectors = []struct {
+	len int
+	sum string
+}{
+	{0, "af1349b9f5f9a1a6a0404dea36dcc9499bcb25c9adc112b7cc9a93cae41f3262"},
+	{1, "2d3adedff11b61f14c886e35afa036736dcd87a74d27b5c1510225d0f592e213"},
+	{63, "e9bc37a594daad83be9470df7f7b3798297c3d834ce80ba85d6e207627b7db7b"},
+	{64, "4eed7141ea4a5cd4b788606bd23f46e212af9cacebacdc7d1f4c6dc7f2511b98"},
+	{65, "de1e5fa0be70df6d2be8fffd0e99ceaa8eb6e8c93a63f2d8d1c30ecb6b263dee"},
+	{1023, "10108970eeda3eb932baac1428c7a2163b0e924c9a9e25b35bba72b28f70bd11"},
+	{1024, "42214739f095a406f3fc83deb889744ac00df831c10daa55189b5d121c855af7"},
+	{1025, "d00278ae47eb27b34faecf67b4fe263f82d5412916c1ffd97c8cb7fb814b8444"},
+	{2048, "e776b6028c7cd22a4d0ba182a8bf62205d2ef576467e838ed6f2529b85fba24a"},
+	{2049, "5f4d72f40d7a5f82b15ca2b2e44b1de3c2ef86c426c95c1af0b6879522563030"},
+	{3072, "b98cb0ff3623be03326b373de6b9095218513e64f1ee2edd2525c7ad1e5cffd2"},
+	{3073, "7124b49501012f81cc7f11ca069ec9226cecb8a2c850cfe644e327d22d3e1cd3"},
+	{4096, "015094013f57a5277b59d8475c0501042c0b642e531b0a1c8f58d2163229e969"},
+	{4097, "9b4052b38f1c5fc8b1f9ff7ac7b27cd242487b3d890d15c96a1c25b8aa0fb995"},
+	{5120, "9cadc15fed8b5d854562b26a9536d9707cadeda9b143978f319ab34230535833"},
+	{8192, "aae792484c8efe4f19e2ca7d371d8c467ffb10748d8a5a1ae579948f718a2a63"},
+	{8193, "bab6c09cb8ce8cf459261398d2e7aef35700bf488116ceb94a36d0f5f1b7bc3b"},
+	{31744, "62b6960e1a44bcc1eb1a611a8d6235b6b4b78f32e7abc4fb4c6cdcce94895c47"},
+	{102400, "bc3e3d41a1146b069abffad3c0d44860cf664390afce4d9661f7902e7943e085"},
+}
+
+// vectorInput returns the input of a test vector.
+func vectorInput(n int) []byte {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte(i % 251)
+	}
+	return b
+}
+
+// TestSum256 checks Sum256 against the reference vectors.
+func TestSum256(t *testing.T) {
+	for _, v := range hashVectors {
+		sum := Sum256(vectorInput(v.len))
+		if got := hex.EncodeToString(sum[:]); got != v.sum {
+			t.Errorf("Sum256 of %d bytes: got %s, want %s", v.len,
+				got, v.sum)
+		}
+	}
+}
+
+// TestHash checks the hash.Hash of New against the reference vectors, writing
+// the input at once and in uneven pieces that cross block and chunk
+// boundaries.
+func TestHash(t *testing.T) {
+	for _, v := range hashVectors {
+		msg := vectorInput(v.len)
+
+		h := New()
+		if h.Size() != Size || h.BlockSize() != BlockSize {
+			t.Fatalf("size %d, block size %d", h.Size(), h.BlockSize())
+		}
+		h.Write(msg)
+		if got := hex.EncodeToString(h.Sum(nil)); got != v.sum {
+			t.Errorf("New of %d bytes: got %s, want %s", v.len, got,
+				v.sum)
+		}
+
+		for _, piece := range []int{1, 7, 64, 1000} {
+			h.Reset()
+			for p := msg; len(p) > 0; {
+				n := piece
+				if n > len(p) {
+					n = len(p)
+				}
+				h.Write(p[:n])
+				p = p[n:]
+			}
+			if got := hex.EncodeToString(h.Sum(nil)); got != v.sum {
+				t.Errorf("New of %d bytes written %d at a "+
+					"time: got %s, want %s", v.len, piece,
+					got, v.sum)
+			}
+		}
+	}
+}
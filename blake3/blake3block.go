@@ -0,0 +1,92 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blake3
+
+import "math/bits"
+
+// Domain separation flags mixed into every compression.
+const (
+	FlagChunkStart = 1 << 0
+	FlagChunkEnd   = 1 << 1
+	FlagParent     = 1 << 2
+	FlagRoot       = 1 << 3
+)
+
+// IV is the BLAKE3 initialization vector, which doubles as the key of the
+// unkeyed hash.
+var IV = [8]uint32{
+	0x6A09E667, 0xBB67AE85, 0x3C6EF372, 0xA54FF53A,
+	0x510E527F, 0x9B05688C, 0x1F83D9AB, 0x5BE0CD19}
+
+// schedule holds the message word order for each of the seven rounds, which
+// is the identity permuted once more for every round.
+var schedule = [7][16]uint8{
+	{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+	{2, 6, 3, 10, 7, 0, 4, 13, 1, 11, 12, 5, 9, 14, 15, 8},
+	{3, 4, 10, 12, 13, 2, 7, 14, 6, 5, 9, 0, 11, 15, 8, 1},
+	{10, 7, 12, 9, 14, 3, 13, 15, 4, 0, 11, 2, 5, 8, 1, 6},
+	{12, 13, 9, 11, 15, 10, 14, 8, 7, 2, 5, 3, 0, 1, 6, 4},
+	{9, 14, 11, 5, 8, 12, 15, 1, 13, 3, 0, 10, 2, 6, 4, 7},
+	{11, 15, 5, 0, 1, 9, 8, 6, 14, 10, 2, 12, 3, 4, 7, 13},
+}
+
+// g is the BLAKE3 quarter round.
+func g(a, b, c, d, mx, my uint32) (uint32, uint32, uint32, uint32) {
+	a += b + mx
+	d = bits.RotateLeft32(d^a, -16)
+	c += d
+	b = bits.RotateLeft32(b^c, -12)
+	a += b + my
+	d = bits.RotateLeft32(d^a, -8)
+	c += d
+	b = bits.RotateLeft32(b^c, -7)
+	return a, b, c, d
+}
+
+// compress runs the seven rounds of the compression function and returns the
+// full 16 word state before the feed forward.
+func compress(h *[8]uint32, m *[16]uint32, counter uint64, blockLen, flags uint32) [16]uint32 {
+	v0, v1, v2, v3, v4, v5, v6, v7 := h[0], h[1], h[2], h[3], h[4], h[5], h[6], h[7]
+	v8, v9, v10, v11 := IV[0], IV[1], IV[2], IV[3]
+	v12, v13, v14, v15 := uint32(counter), uint32(counter>>32), blockLen, flags
+
+	for r := range schedule {
+		s := &schedule[r]
+		v0, v4, v8, v12 = g(v0, v4, v8, v12, m[s[0]], m[s[1]])
+		v1, v5, v9, v13 = g(v1, v5, v9, v13, m[s[2]], m[s[3]])
+		v2, v6, v10, v14 = g(v2, v6, v10, v14, m[s[4]], m[s[5]])
+		v3, v7, v11, v15 = g(v3, v7, v11, v15, m[s[6]], m[s[7]])
+		v0, v5, v10, v15 = g(v0, v5, v10, v15, m[s[8]], m[s[9]])
+		v1, v6, v11, v12 = g(v1, v6, v11, v12, m[s[10]], m[s[11]])
+		v2, v7, v8, v13 = g(v2, v7, v8, v13, m[s[12]], m[s[13]])
+		v3, v4, v9, v14 = g(v3, v4, v9, v14, m[s[14]], m[s[15]])
+	}
+
+	return [16]uint32{v0, v1, v2, v3, v4, v5, v6, v7,
+		v8, v9, v10, v11, v12, v13, v14, v15}
+}
+
+// Block compresses the message block 'm' into the chaining value 'h'.
+// 'blockLen' is the number of message bytes in the block and 'flags' the
+// domain separation flags.  Only the first 8 words of the output are kept,
+// which is all that is needed for chaining and for a 32 byte root hash.
+func Block(h *[8]uint32, m *[16]uint32, counter uint64, blockLen, flags uint32) {
+	v := compress(h, m, counter, blockLen, flags)
+	for i := range h {
+		h[i] = v[i] ^ v[i+8]
+	}
+}
+
+// blockWords converts a block of up to BlockSize bytes to little endian
+// message words, zero padding it as needed.
+func blockWords(m *[16]uint32, p []byte) {
+	var buf [BlockSize]byte
+	copy(buf[:], p)
+	for i := range m {
+		j := i * 4
+		m[i] = uint32(buf[j]) | uint32(buf[j+1])<<8 |
+			uint32(buf[j+2])<<16 | uint32(buf[j+3])<<24
+	}
+}
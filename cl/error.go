@@ -0,0 +1,89 @@
+// Copyright (c) 2026 The Decred developers
+
+package cl
+
+import "fmt"
+
+// Error is an error status returned by an OpenCL function.  Use errors.As to
+// get at the status of a returned error, or errors.Is with one of the error
+// values below to check for a particular status regardless of the function
+// that returned it.
+type Error struct {
+	// Code is the status returned by the function.
+	Code CL_int
+
+	// Name is the name of the status, such as CL_OUT_OF_RESOURCES.
+	Name string
+
+	// Function is the name of the function that failed.
+	Function string
+}
+
+// Errors for the statuses callers commonly check for.
+var (
+	ErrDeviceNotFound             = &Error{Code: CL_DEVICE_NOT_FOUND, Name: ErrorName(CL_DEVICE_NOT_FOUND)}
+	ErrDeviceNotAvailable         = &Error{Code: CL_DEVICE_NOT_AVAILABLE, Name: ErrorName(CL_DEVICE_NOT_AVAILABLE)}
+	ErrMemObjectAllocationFailure = &Error{Code: CL_MEM_OBJECT_ALLOCATION_FAILURE, Name: ErrorName(CL_MEM_OBJECT_ALLOCATION_FAILURE)}
+	ErrOutOfResources             = &Error{Code: CL_OUT_OF_RESOURCES, Name: ErrorName(CL_OUT_OF_RESOURCES)}
+	ErrOutOfHostMemory            = &Error{Code: CL_OUT_OF_HOST_MEMORY, Name: ErrorName(CL_OUT_OF_HOST_MEMORY)}
+	ErrBuildProgramFailure        = &Error{Code: CL_BUILD_PROGRAM_FAILURE, Name: ErrorName(CL_BUILD_PROGRAM_FAILURE)}
+	ErrInvalidBinary              = &Error{Code: CL_INVALID_BINARY, Name: ErrorName(CL_INVALID_BINARY)}
+)
+
+// CL_PLATFORM_NOT_FOUND_KHR is returned by the ICD loader when no platform is
+// installed.
+const CL_PLATFORM_NOT_FOUND_KHR CL_int = -1001
+
+// extensionErrorNames names the statuses of extensions commonly seen from
+// the ICD loader, which are missing from ERROR_CODES_STRINGS.
+var extensionErrorNames = map[CL_int]string{
+	CL_PLATFORM_NOT_FOUND_KHR: "CL_PLATFORM_NOT_FOUND_KHR",
+}
+
+// ErrorName returns the name of an OpenCL status.  Unknown statuses, such as
+// those of other vendor extensions, are named after their code.
+func ErrorName(code CL_int) string {
+	if code <= 0 && int(-code) < len(ERROR_CODES_STRINGS) {
+		return ERROR_CODES_STRINGS[-code]
+	}
+	if name, ok := extensionErrorNames[code]; ok {
+		return name
+	}
+	return fmt.Sprintf("CL_UNKNOWN_ERROR_%d", code)
+}
+
+// statusError returns the error for a status returned by the named function.
+func statusError(status CL_int, function string) error {
+	return &Error{
+		Code:     status,
+		Name:     ErrorName(status),
+		Function: function,
+	}
+}
+
+// Error returns the error as a string.
+func (e *Error) Error() string {
+	if e.Function == "" {
+		return fmt.Sprintf("%s (%d)", e.Name, e.Code)
+	}
+	return fmt.Sprintf("%s returned error %s (%d)", e.Function, e.Name,
+		e.Code)
+}
+
+// Is reports whether target is an *Error with the same status.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// Temporary reports whether the failure is caused by a shortage of device or
+// host resources, which may go away when retried later, rather than by an
+// invalid call or a broken device.
+func (e *Error) Temporary() bool {
+	switch e.Code {
+	case CL_MEM_OBJECT_ALLOCATION_FAILURE, CL_OUT_OF_RESOURCES,
+		CL_OUT_OF_HOST_MEMORY:
+		return true
+	}
+	return false
+}
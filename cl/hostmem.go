@@ -0,0 +1,57 @@
+// Copyright (c) 2026 The Decred developers
+
+package cl
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// maxHostMemWords bounds the size of host memory so that it can be used as a
+// slice on every architecture.
+const maxHostMemWords = 1 << 28
+
+// HostMem is host memory allocated outside of the Go heap.  OpenCL keeps using
+// the host memory of a non-blocking transfer after the call queueing it
+// returns, which cgo does not allow for Go memory, so transfers are made to
+// and from HostMem.
+type HostMem struct {
+	p     unsafe.Pointer
+	words int
+}
+
+// NewHostMem allocates zeroed host memory of the given number of 32-bit
+// words.  It must be freed with Close once no queued transfer uses it.
+func NewHostMem(words int) (*HostMem, error) {
+	if words <= 0 || words > maxHostMemWords {
+		return nil, fmt.Errorf("Invalid host memory size of %d words",
+			words)
+	}
+	p := C.calloc(C.size_t(words), 4)
+	if p == nil {
+		return nil, fmt.Errorf("Unable to allocate %d words of host "+
+			"memory", words)
+	}
+	return &HostMem{p: p, words: words}, nil
+}
+
+// Words returns the host memory as a slice, which must not be used after
+// Close.
+func (h *HostMem) Words() []uint32 {
+	return (*[maxHostMemWords]uint32)(h.p)[:h.words:h.words]
+}
+
+// Close frees the host memory.
+func (h *HostMem) Close() error {
+	if h == nil || h.p == nil {
+		return nil
+	}
+	C.free(h.p)
+	h.p = nil
+	return nil
+}
@@ -0,0 +1,529 @@
+// Copyright (c) 2026 The Decred developers
+
+// The OpenCL library is loaded at run time rather than linked so that the
+// miner starts on systems without OpenCL.  This file defines every OpenCL
+// function used by the bindings as a trampoline to the function of the same
+// name in the library loaded by gominer_cl_load.  Until the library is loaded,
+// or when it lacks a function, the trampolines fail with CL_INVALID_OPERATION.
+//
+// The OpenCL headers are not included since they declare these very
+// functions.  The types below have the same layout as theirs.
+
+#include <stddef.h>
+#include <stdint.h>
+#include <stdio.h>
+
+#ifdef _WIN32
+#include <windows.h>
+#define CL_API_CALL __stdcall
+#define CL_CALLBACK __stdcall
+#else
+#include <dlfcn.h>
+#define CL_API_CALL
+#define CL_CALLBACK
+#endif
+
+#include "loader.h"
+
+typedef int32_t cl_int;
+typedef uint32_t cl_uint;
+typedef uint64_t cl_ulong;
+typedef cl_uint cl_bool;
+typedef cl_ulong cl_bitfield;
+
+typedef struct _cl_platform_id *cl_platform_id;
+typedef struct _cl_device_id *cl_device_id;
+typedef struct _cl_context *cl_context;
+typedef struct _cl_command_queue *cl_command_queue;
+typedef struct _cl_mem *cl_mem;
+typedef struct _cl_program *cl_program;
+typedef struct _cl_kernel *cl_kernel;
+typedef struct _cl_event *cl_event;
+typedef struct _cl_sampler *cl_sampler;
+typedef struct _cl_image_format cl_image_format;
+
+typedef intptr_t cl_context_properties;
+typedef cl_bitfield cl_device_type;
+typedef cl_bitfield cl_mem_flags;
+typedef cl_bitfield cl_map_flags;
+typedef cl_bitfield cl_command_queue_properties;
+typedef cl_uint cl_platform_info;
+typedef cl_uint cl_device_info;
+typedef cl_uint cl_context_info;
+typedef cl_uint cl_command_queue_info;
+typedef cl_uint cl_mem_object_type;
+typedef cl_uint cl_mem_info;
+typedef cl_uint cl_image_info;
+typedef cl_uint cl_buffer_create_type;
+typedef cl_uint cl_addressing_mode;
+typedef cl_uint cl_filter_mode;
+typedef cl_uint cl_sampler_info;
+typedef cl_uint cl_program_info;
+typedef cl_uint cl_program_build_info;
+typedef cl_uint cl_kernel_info;
+typedef cl_uint cl_kernel_work_group_info;
+typedef cl_uint cl_event_info;
+typedef cl_uint cl_profiling_info;
+
+#define CL_INVALID_OPERATION -59
+
+// CL_FUNCTIONS lists the functions along with their parameters and the
+// arguments passing them on.  STATUS functions return a status, OBJECT
+// functions return an object and set the status through errcode_ret.
+#define CL_FUNCTIONS \
+	/* Platforms and devices */ \
+	STATUS(clGetPlatformIDs, \
+		(cl_uint num_entries, cl_platform_id *platforms, \
+		cl_uint *num_platforms), \
+		(num_entries, platforms, num_platforms)) \
+	STATUS(clGetPlatformInfo, \
+		(cl_platform_id platform, cl_platform_info param_name, \
+		size_t param_value_size, void *param_value, \
+		size_t *param_value_size_ret), \
+		(platform, param_name, param_value_size, param_value, \
+		param_value_size_ret)) \
+	STATUS(clGetDeviceIDs, \
+		(cl_platform_id platform, cl_device_type device_type, \
+		cl_uint num_entries, cl_device_id *devices, \
+		cl_uint *num_devices), \
+		(platform, device_type, num_entries, devices, num_devices)) \
+	STATUS(clGetDeviceInfo, \
+		(cl_device_id device, cl_device_info param_name, \
+		size_t param_value_size, void *param_value, \
+		size_t *param_value_size_ret), \
+		(device, param_name, param_value_size, param_value, \
+		param_value_size_ret)) \
+	\
+	/* Contexts */ \
+	OBJECT(cl_context, clCreateContext, \
+		(const cl_context_properties *properties, cl_uint num_devices, \
+		const cl_device_id *devices, \
+		void (CL_CALLBACK *pfn_notify)(const char *, const void *, \
+		size_t, void *), \
+		void *user_data, cl_int *errcode_ret), \
+		(properties, num_devices, devices, pfn_notify, user_data, \
+		errcode_ret)) \
+	OBJECT(cl_context, clCreateContextFromType, \
+		(const cl_context_properties *properties, \
+		cl_device_type device_type, \
+		void (CL_CALLBACK *pfn_notify)(const char *, const void *, \
+		size_t, void *), \
+		void *user_data, cl_int *errcode_ret), \
+		(properties, device_type, pfn_notify, user_data, errcode_ret)) \
+	STATUS(clRetainContext, (cl_context context), (context)) \
+	STATUS(clReleaseContext, (cl_context context), (context)) \
+	STATUS(clGetContextInfo, \
+		(cl_context context, cl_context_info param_name, \
+		size_t param_value_size, void *param_value, \
+		size_t *param_value_size_ret), \
+		(context, param_name, param_value_size, param_value, \
+		param_value_size_ret)) \
+	\
+	/* Command queues */ \
+	OBJECT(cl_command_queue, clCreateCommandQueue, \
+		(cl_context context, cl_device_id device, \
+		cl_command_queue_properties properties, cl_int *errcode_ret), \
+		(context, device, properties, errcode_ret)) \
+	STATUS(clRetainCommandQueue, (cl_command_queue command_queue), \
+		(command_queue)) \
+	STATUS(clReleaseCommandQueue, (cl_command_queue command_queue), \
+		(command_queue)) \
+	STATUS(clGetCommandQueueInfo, \
+		(cl_command_queue command_queue, \
+		cl_command_queue_info param_name, size_t param_value_size, \
+		void *param_value, size_t *param_value_size_ret), \
+		(command_queue, param_name, param_value_size, param_value, \
+		param_value_size_ret)) \
+	STATUS(clFlush, (cl_command_queue command_queue), (command_queue)) \
+	STATUS(clFinish, (cl_command_queue command_queue), (command_queue)) \
+	\
+	/* Memory objects */ \
+	OBJECT(cl_mem, clCreateBuffer, \
+		(cl_context context, cl_mem_flags flags, size_t size, \
+		void *host_ptr, cl_int *errcode_ret), \
+		(context, flags, size, host_ptr, errcode_ret)) \
+	OBJECT(cl_mem, clCreateSubBuffer, \
+		(cl_mem buffer, cl_mem_flags flags, \
+		cl_buffer_create_type buffer_create_type, \
+		const void *buffer_create_info, cl_int *errcode_ret), \
+		(buffer, flags, buffer_create_type, buffer_create_info, \
+		errcode_ret)) \
+	OBJECT(cl_mem, clCreateImage2D, \
+		(cl_context context, cl_mem_flags flags, \
+		const cl_image_format *image_format, size_t image_width, \
+		size_t image_height, size_t image_row_pitch, void *host_ptr, \
+		cl_int *errcode_ret), \
+		(context, flags, image_format, image_width, image_height, \
+		image_row_pitch, host_ptr, errcode_ret)) \
+	OBJECT(cl_mem, clCreateImage3D, \
+		(cl_context context, cl_mem_flags flags, \
+		const cl_image_format *image_format, size_t image_width, \
+		size_t image_height, size_t image_depth, \
+		size_t image_row_pitch, size_t image_slice_pitch, \
+		void *host_ptr, cl_int *errcode_ret), \
+		(context, flags, image_format, image_width, image_height, \
+		image_depth, image_row_pitch, image_slice_pitch, host_ptr, \
+		errcode_ret)) \
+	STATUS(clRetainMemObject, (cl_mem memobj), (memobj)) \
+	STATUS(clReleaseMemObject, (cl_mem memobj), (memobj)) \
+	STATUS(clGetSupportedImageFormats, \
+		(cl_context context, cl_mem_flags flags, \
+		cl_mem_object_type image_type, cl_uint num_entries, \
+		cl_image_format *image_formats, cl_uint *num_image_formats), \
+		(context, flags, image_type, num_entries, image_formats, \
+		num_image_formats)) \
+	STATUS(clGetMemObjectInfo, \
+		(cl_mem memobj, cl_mem_info param_name, \
+		size_t param_value_size, void *param_value, \
+		size_t *param_value_size_ret), \
+		(memobj, param_name, param_value_size, param_value, \
+		param_value_size_ret)) \
+	STATUS(clGetImageInfo, \
+		(cl_mem image, cl_image_info param_name, \
+		size_t param_value_size, void *param_value, \
+		size_t *param_value_size_ret), \
+		(image, param_name, param_value_size, param_value, \
+		param_value_size_ret)) \
+	STATUS(clSetMemObjectDestructorCallback, \
+		(cl_mem memobj, \
+		void (CL_CALLBACK *pfn_notify)(cl_mem, void *), \
+		void *user_data), \
+		(memobj, pfn_notify, user_data)) \
+	\
+	/* Samplers */ \
+	OBJECT(cl_sampler, clCreateSampler, \
+		(cl_context context, cl_bool normalized_coords, \
+		cl_addressing_mode addressing_mode, cl_filter_mode filter_mode, \
+		cl_int *errcode_ret), \
+		(context, normalized_coords, addressing_mode, filter_mode, \
+		errcode_ret)) \
+	STATUS(clRetainSampler, (cl_sampler sampler), (sampler)) \
+	STATUS(clReleaseSampler, (cl_sampler sampler), (sampler)) \
+	STATUS(clGetSamplerInfo, \
+		(cl_sampler sampler, cl_sampler_info param_name, \
+		size_t param_value_size, void *param_value, \
+		size_t *param_value_size_ret), \
+		(sampler, param_name, param_value_size, param_value, \
+		param_value_size_ret)) \
+	\
+	/* Programs */ \
+	OBJECT(cl_program, clCreateProgramWithSource, \
+		(cl_context context, cl_uint count, const char **strings, \
+		const size_t *lengths, cl_int *errcode_ret), \
+		(context, count, strings, lengths, errcode_ret)) \
+	OBJECT(cl_program, clCreateProgramWithBinary, \
+		(cl_context context, cl_uint num_devices, \
+		const cl_device_id *device_list, const size_t *lengths, \
+		const unsigned char **binaries, cl_int *binary_status, \
+		cl_int *errcode_ret), \
+		(context, num_devices, device_list, lengths, binaries, \
+		binary_status, errcode_ret)) \
+	STATUS(clRetainProgram, (cl_program program), (program)) \
+	STATUS(clReleaseProgram, (cl_program program), (program)) \
+	STATUS(clBuildProgram, \
+		(cl_program program, cl_uint num_devices, \
+		const cl_device_id *device_list, const char *options, \
+		void (CL_CALLBACK *pfn_notify)(cl_program, void *), \
+		void *user_data), \
+		(program, num_devices, device_list, options, pfn_notify, \
+		user_data)) \
+	STATUS(clUnloadCompiler, (void), ()) \
+	STATUS(clGetProgramInfo, \
+		(cl_program program, cl_program_info param_name, \
+		size_t param_value_size, void *param_value, \
+		size_t *param_value_size_ret), \
+		(program, param_name, param_value_size, param_value, \
+		param_value_size_ret)) \
+	STATUS(clGetProgramBuildInfo, \
+		(cl_program program, cl_device_id device, \
+		cl_program_build_info param_name, size_t param_value_size, \
+		void *param_value, size_t *param_value_size_ret), \
+		(program, device, param_name, param_value_size, param_value, \
+		param_value_size_ret)) \
+	\
+	/* Kernels */ \
+	OBJECT(cl_kernel, clCreateKernel, \
+		(cl_program program, const char *kernel_name, \
+		cl_int *errcode_ret), \
+		(program, kernel_name, errcode_ret)) \
+	STATUS(clCreateKernelsInProgram, \
+		(cl_program program, cl_uint num_kernels, cl_kernel *kernels, \
+		cl_uint *num_kernels_ret), \
+		(program, num_kernels, kernels, num_kernels_ret)) \
+	STATUS(clRetainKernel, (cl_kernel kernel), (kernel)) \
+	STATUS(clReleaseKernel, (cl_kernel kernel), (kernel)) \
+	STATUS(clSetKernelArg, \
+		(cl_kernel kernel, cl_uint arg_index, size_t arg_size, \
+		const void *arg_value), \
+		(kernel, arg_index, arg_size, arg_value)) \
+	STATUS(clGetKernelInfo, \
+		(cl_kernel kernel, cl_kernel_info param_name, \
+		size_t param_value_size, void *param_value, \
+		size_t *param_value_size_ret), \
+		(kernel, param_name, param_value_size, param_value, \
+		param_value_size_ret)) \
+	STATUS(clGetKernelWorkGroupInfo, \
+		(cl_kernel kernel, cl_device_id device, \
+		cl_kernel_work_group_info param_name, size_t param_value_size, \
+		void *param_value, size_t *param_value_size_ret), \
+		(kernel, device, param_name, param_value_size, param_value, \
+		param_value_size_ret)) \
+	\
+	/* Events */ \
+	STATUS(clWaitForEvents, \
+		(cl_uint num_events, const cl_event *event_list), \
+		(num_events, event_list)) \
+	STATUS(clGetEventInfo, \
+		(cl_event event, cl_event_info param_name, \
+		size_t param_value_size, void *param_value, \
+		size_t *param_value_size_ret), \
+		(event, param_name, param_value_size, param_value, \
+		param_value_size_ret)) \
+	OBJECT(cl_event, clCreateUserEvent, \
+		(cl_context context, cl_int *errcode_ret), \
+		(context, errcode_ret)) \
+	STATUS(clRetainEvent, (cl_event event), (event)) \
+	STATUS(clReleaseEvent, (cl_event event), (event)) \
+	STATUS(clSetUserEventStatus, \
+		(cl_event event, cl_int execution_status), \
+		(event, execution_status)) \
+	STATUS(clSetEventCallback, \
+		(cl_event event, cl_int command_exec_callback_type, \
+		void (CL_CALLBACK *pfn_notify)(cl_event, cl_int, void *), \
+		void *user_data), \
+		(event, command_exec_callback_type, pfn_notify, user_data)) \
+	STATUS(clGetEventProfilingInfo, \
+		(cl_event event, cl_profiling_info param_name, \
+		size_t param_value_size, void *param_value, \
+		size_t *param_value_size_ret), \
+		(event, param_name, param_value_size, param_value, \
+		param_value_size_ret)) \
+	\
+	/* Enqueued commands */ \
+	STATUS(clEnqueueReadBuffer, \
+		(cl_command_queue command_queue, cl_mem buffer, \
+		cl_bool blocking_read, size_t offset, size_t size, void *ptr, \
+		cl_uint num_events_in_wait_list, \
+		const cl_event *event_wait_list, cl_event *event), \
+		(command_queue, buffer, blocking_read, offset, size, ptr, \
+		num_events_in_wait_list, event_wait_list, event)) \
+	STATUS(clEnqueueReadBufferRect, \
+		(cl_command_queue command_queue, cl_mem buffer, \
+		cl_bool blocking_read, const size_t *buffer_offset, \
+		const size_t *host_offset, const size_t *region, \
+		size_t buffer_row_pitch, size_t buffer_slice_pitch, \
+		size_t host_row_pitch, size_t host_slice_pitch, void *ptr, \
+		cl_uint num_events_in_wait_list, \
+		const cl_event *event_wait_list, cl_event *event), \
+		(command_queue, buffer, blocking_read, buffer_offset, \
+		host_offset, region, buffer_row_pitch, buffer_slice_pitch, \
+		host_row_pitch, host_slice_pitch, ptr, \
+		num_events_in_wait_list, event_wait_list, event)) \
+	STATUS(clEnqueueWriteBuffer, \
+		(cl_command_queue command_queue, cl_mem buffer, \
+		cl_bool blocking_write, size_t offset, size_t size, \
+		const void *ptr, cl_uint num_events_in_wait_list, \
+		const cl_event *event_wait_list, cl_event *event), \
+		(command_queue, buffer, blocking_write, offset, size, ptr, \
+		num_events_in_wait_list, event_wait_list, event)) \
+	STATUS(clEnqueueWriteBufferRect, \
+		(cl_command_queue command_queue, cl_mem buffer, \
+		cl_bool blocking_write, const size_t *buffer_offset, \
+		const size_t *host_offset, const size_t *region, \
+		size_t buffer_row_pitch, size_t buffer_slice_pitch, \
+		size_t host_row_pitch, size_t host_slice_pitch, \
+		const void *ptr, cl_uint num_events_in_wait_list, \
+		const cl_event *event_wait_list, cl_event *event), \
+		(command_queue, buffer, blocking_write, buffer_offset, \
+		host_offset, region, buffer_row_pitch, buffer_slice_pitch, \
+		host_row_pitch, host_slice_pitch, ptr, \
+		num_events_in_wait_list, event_wait_list, event)) \
+	STATUS(clEnqueueCopyBuffer, \
+		(cl_command_queue command_queue, cl_mem src_buffer, \
+		cl_mem dst_buffer, size_t src_offset, size_t dst_offset, \
+		size_t size, cl_uint num_events_in_wait_list, \
+		const cl_event *event_wait_list, cl_event *event), \
+		(command_queue, src_buffer, dst_buffer, src_offset, dst_offset, \
+		size, num_events_in_wait_list, event_wait_list, event)) \
+	STATUS(clEnqueueCopyBufferRect, \
+		(cl_command_queue command_queue, cl_mem src_buffer, \
+		cl_mem dst_buffer, const size_t *src_origin, \
+		const size_t *dst_origin, const size_t *region, \
+		size_t src_row_pitch, size_t src_slice_pitch, \
+		size_t dst_row_pitch, size_t dst_slice_pitch, \
+		cl_uint num_events_in_wait_list, \
+		const cl_event *event_wait_list, cl_event *event), \
+		(command_queue, src_buffer, dst_buffer, src_origin, dst_origin, \
+		region, src_row_pitch, src_slice_pitch, dst_row_pitch, \
+		dst_slice_pitch, num_events_in_wait_list, event_wait_list, \
+		event)) \
+	STATUS(clEnqueueReadImage, \
+		(cl_command_queue command_queue, cl_mem image, \
+		cl_bool blocking_read, const size_t *origin, \
+		const size_t *region, size_t row_pitch, size_t slice_pitch, \
+		void *ptr, cl_uint num_events_in_wait_list, \
+		const cl_event *event_wait_list, cl_event *event), \
+		(command_queue, image, blocking_read, origin, region, row_pitch, \
+		slice_pitch, ptr, num_events_in_wait_list, event_wait_list, \
+		event)) \
+	STATUS(clEnqueueWriteImage, \
+		(cl_command_queue command_queue, cl_mem image, \
+		cl_bool blocking_write, const size_t *origin, \
+		const size_t *region, size_t input_row_pitch, \
+		size_t input_slice_pitch, const void *ptr, \
+		cl_uint num_events_in_wait_list, \
+		const cl_event *event_wait_list, cl_event *event), \
+		(command_queue, image, blocking_write, origin, region, \
+		input_row_pitch, input_slice_pitch, ptr, \
+		num_events_in_wait_list, event_wait_list, event)) \
+	STATUS(clEnqueueCopyImage, \
+		(cl_command_queue command_queue, cl_mem src_image, \
+		cl_mem dst_image, const size_t *src_origin, \
+		const size_t *dst_origin, const size_t *region, \
+		cl_uint num_events_in_wait_list, \
+		const cl_event *event_wait_list, cl_event *event), \
+		(command_queue, src_image, dst_image, src_origin, dst_origin, \
+		region, num_events_in_wait_list, event_wait_list, event)) \
+	STATUS(clEnqueueCopyImageToBuffer, \
+		(cl_command_queue command_queue, cl_mem src_image, \
+		cl_mem dst_buffer, const size_t *src_origin, \
+		const size_t *region, size_t dst_offset, \
+		cl_uint num_events_in_wait_list, \
+		const cl_event *event_wait_list, cl_event *event), \
+		(command_queue, src_image, dst_buffer, src_origin, region, \
+		dst_offset, num_events_in_wait_list, event_wait_list, event)) \
+	STATUS(clEnqueueCopyBufferToImage, \
+		(cl_command_queue command_queue, cl_mem src_buffer, \
+		cl_mem dst_image, size_t src_offset, const size_t *dst_origin, \
+		const size_t *region, cl_uint num_events_in_wait_list, \
+		const cl_event *event_wait_list, cl_event *event), \
+		(command_queue, src_buffer, dst_image, src_offset, dst_origin, \
+		region, num_events_in_wait_list, event_wait_list, event)) \
+	OBJECT(void *, clEnqueueMapBuffer, \
+		(cl_command_queue command_queue, cl_mem buffer, \
+		cl_bool blocking_map, cl_map_flags map_flags, size_t offset, \
+		size_t size, cl_uint num_events_in_wait_list, \
+		const cl_event *event_wait_list, cl_event *event, \
+		cl_int *errcode_ret), \
+		(command_queue, buffer, blocking_map, map_flags, offset, size, \
+		num_events_in_wait_list, event_wait_list, event, errcode_ret)) \
+	OBJECT(void *, clEnqueueMapImage, \
+		(cl_command_queue command_queue, cl_mem image, \
+		cl_bool blocking_map, cl_map_flags map_flags, \
+		const size_t *origin, const size_t *region, \
+		size_t *image_row_pitch, size_t *image_slice_pitch, \
+		cl_uint num_events_in_wait_list, \
+		const cl_event *event_wait_list, cl_event *event, \
+		cl_int *errcode_ret), \
+		(command_queue, image, blocking_map, map_flags, origin, region, \
+		image_row_pitch, image_slice_pitch, num_events_in_wait_list, \
+		event_wait_list, event, errcode_ret)) \
+	STATUS(clEnqueueUnmapMemObject, \
+		(cl_command_queue command_queue, cl_mem memobj, \
+		void *mapped_ptr, cl_uint num_events_in_wait_list, \
+		const cl_event *event_wait_list, cl_event *event), \
+		(command_queue, memobj, mapped_ptr, num_events_in_wait_list, \
+		event_wait_list, event)) \
+	STATUS(clEnqueueNDRangeKernel, \
+		(cl_command_queue command_queue, cl_kernel kernel, \
+		cl_uint work_dim, const size_t *global_work_offset, \
+		const size_t *global_work_size, const size_t *local_work_size, \
+		cl_uint num_events_in_wait_list, \
+		const cl_event *event_wait_list, cl_event *event), \
+		(command_queue, kernel, work_dim, global_work_offset, \
+		global_work_size, local_work_size, num_events_in_wait_list, \
+		event_wait_list, event)) \
+	STATUS(clEnqueueTask, \
+		(cl_command_queue command_queue, cl_kernel kernel, \
+		cl_uint num_events_in_wait_list, \
+		const cl_event *event_wait_list, cl_event *event), \
+		(command_queue, kernel, num_events_in_wait_list, \
+		event_wait_list, event)) \
+	STATUS(clEnqueueMarker, \
+		(cl_command_queue command_queue, cl_event *event), \
+		(command_queue, event)) \
+	STATUS(clEnqueueWaitForEvents, \
+		(cl_command_queue command_queue, cl_uint num_events, \
+		const cl_event *event_list), \
+		(command_queue, num_events, event_list)) \
+	STATUS(clEnqueueBarrier, (cl_command_queue command_queue), \
+		(command_queue))
+
+// Define the pointers to the library functions and the trampolines.
+#define STATUS(name, params, args) \
+	static cl_int (CL_API_CALL *p_##name) params; \
+	cl_int CL_API_CALL name params \
+	{ \
+		if (p_##name == NULL) \
+			return CL_INVALID_OPERATION; \
+		return p_##name args; \
+	}
+#define OBJECT(type, name, params, args) \
+	static type (CL_API_CALL *p_##name) params; \
+	type CL_API_CALL name params \
+	{ \
+		if (p_##name == NULL) { \
+			if (errcode_ret != NULL) \
+				*errcode_ret = CL_INVALID_OPERATION; \
+			return NULL; \
+		} \
+		return p_##name args; \
+	}
+CL_FUNCTIONS
+#undef STATUS
+#undef OBJECT
+
+// functions maps the function names to their pointers.
+#define STATUS(name, params, args) { #name, (void **)&p_##name },
+#define OBJECT(type, name, params, args) { #name, (void **)&p_##name },
+static const struct {
+	const char *name;
+	void **fn;
+} functions[] = {
+	CL_FUNCTIONS
+};
+#undef STATUS
+#undef OBJECT
+
+static char load_error[256];
+
+// gominer_cl_load loads the named OpenCL library and resolves its functions.
+// It returns NULL on success and the reason the library could not be loaded
+// otherwise.  It must not be called again once it succeeded.
+const char *gominer_cl_load(const char *library)
+{
+	size_t i;
+
+#ifdef _WIN32
+	HMODULE lib = LoadLibraryA(library);
+	if (lib == NULL) {
+		snprintf(load_error, sizeof(load_error),
+		    "%s: unable to load library (error %lu)", library,
+		    (unsigned long)GetLastError());
+		return load_error;
+	}
+	if (GetProcAddress(lib, "clGetPlatformIDs") == NULL) {
+		FreeLibrary(lib);
+		snprintf(load_error, sizeof(load_error),
+		    "%s: not an OpenCL library", library);
+		return load_error;
+	}
+	for (i = 0; i < sizeof(functions) / sizeof(functions[0]); i++)
+		*functions[i].fn = (void *)GetProcAddress(lib,
+		    functions[i].name);
+#else
+	void *lib = dlopen(library, RTLD_NOW | RTLD_LOCAL);
+	if (lib == NULL)
+		return dlerror();
+	if (dlsym(lib, "clGetPlatformIDs") == NULL) {
+		dlclose(lib);
+		snprintf(load_error, sizeof(load_error),
+		    "%s: not an OpenCL library", library);
+		return load_error;
+	}
+	for (i = 0; i < sizeof(functions) / sizeof(functions[0]); i++)
+		*functions[i].fn = dlsym(lib, functions[i].name);
+#endif
+
+	return NULL;
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2026 The Decred developers
+
+package cl
+
+/*
+#cgo linux LDFLAGS: -ldl
+
+#include <stdlib.h>
+#include "loader.h"
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"unsafe"
+)
+
+// ErrUnavailable is wrapped by the error Load returns when there is no usable
+// OpenCL library on the system.
+var ErrUnavailable = errors.New("OpenCL unavailable")
+
+// Library, when set before the library is loaded, is the OpenCL library to
+// load instead of the default ones of the system.
+var Library string
+
+var (
+	loadOnce sync.Once
+	loadErr  error
+)
+
+// libraryNames returns the names the OpenCL library is looked for under, in
+// order.
+func libraryNames() []string {
+	if Library != "" {
+		return []string{Library}
+	}
+	switch runtime.GOOS {
+	case "darwin":
+		return []string{"/System/Library/Frameworks/OpenCL.framework/OpenCL"}
+	case "windows":
+		return []string{"OpenCL.dll"}
+	}
+	return []string{"libOpenCL.so.1", "libOpenCL.so"}
+}
+
+// Load loads the OpenCL library.  Nothing in this package works until it was
+// loaded; the functions of the raw bindings fail with CL_INVALID_OPERATION
+// before then.  Platforms loads the library itself, so only users of the raw
+// bindings need to call Load.  It is safe to call Load more than once, and
+// every call returns the result of the first.
+func Load() error {
+	loadOnce.Do(func() {
+		var reasons []string
+		for _, name := range libraryNames() {
+			cName := C.CString(name)
+			reason := C.gominer_cl_load(cName)
+			C.free(unsafe.Pointer(cName))
+			if reason == nil {
+				return
+			}
+			reasons = append(reasons, C.GoString(reason))
+		}
+		loadErr = fmt.Errorf("%w: %s", ErrUnavailable,
+			strings.Join(reasons, "; "))
+	})
+	return loadErr
+}
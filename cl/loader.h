@@ -0,0 +1,8 @@
+// Copyright (c) 2026 The Decred developers
+
+#ifndef GOMINER_CL_LOADER_H
+#define GOMINER_CL_LOADER_H
+
+const char *gominer_cl_load(const char *library);
+
+#endif
@@ -3,8 +3,6 @@ package cl
 
 /*
 #cgo CFLAGS: -I CL
-#cgo !darwin LDFLAGS: -lOpenCL
-#cgo darwin LDFLAGS: -framework OpenCL
 
 #define CL_USE_DEPRECATED_OPENCL_1_1_APIS
 #define CL_USE_DEPRECATED_OPENCL_1_2_APIS
@@ -309,11 +307,24 @@ func CLGetProgramInfo(program CL_program,
 				*param_value = value2
 
 			case CL_PROGRAM_BINARIES:
+				// The caller passes a [][]byte in param_value with a
+				// buffer for each device, sized as returned by
+				// CL_PROGRAM_BINARY_SIZES, which gets filled in.
 				var param *C.uchar
 				length := int(C.size_t(param_value_size) / C.size_t(unsafe.Sizeof(param)))
 
+				binaries, ok := (*param_value).([][]byte)
+				if !ok || len(binaries) != length {
+					return CL_INVALID_VALUE
+				}
+
 				value1 := make([]*C.uchar, length)
-				value2 := make([]*CL_uchar, length)
+				for i := 0; i < length; i++ {
+					if len(binaries[i]) != 0 {
+						value1[i] = (*C.uchar)(C.malloc(C.size_t(len(binaries[i]))))
+						defer C.free(unsafe.Pointer(value1[i]))
+					}
+				}
 
 				c_errcode_ret = C.clGetProgramInfo(program.cl_program,
 					C.cl_program_info(param_name),
@@ -321,12 +332,14 @@ func CLGetProgramInfo(program CL_program,
 					unsafe.Pointer(&value1[0]),
 					&c_param_value_size_ret)
 
-				for i := 0; i < length; i++ {
-					value2[i] = (*CL_uchar)(value1[i])
+				if c_errcode_ret == C.CL_SUCCESS {
+					for i := 0; i < length; i++ {
+						if value1[i] != nil {
+							C.memcpy(unsafe.Pointer(&binaries[i][0]), unsafe.Pointer(value1[i]), C.size_t(len(binaries[i])))
+						}
+					}
 				}
 
-				*param_value = value2
-
 			default:
 				return CL_INVALID_VALUE
 			}
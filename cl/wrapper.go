@@ -0,0 +1,508 @@
+// Copyright (c) 2026 The Decred developers
+
+package cl
+
+// This file holds a typed layer over the raw bindings.  Every call returns an
+// error instead of a status code and every OpenCL object has a Close method
+// releasing it.  Close may be called on nil objects, which makes cleaning up
+// after partial failures simple.
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// Platform is an OpenCL platform.
+type Platform struct {
+	id CL_platform_id
+}
+
+// Platforms loads the OpenCL library if needed and returns every OpenCL
+// platform.
+func Platforms() ([]Platform, error) {
+	err := Load()
+	if err != nil {
+		return nil, err
+	}
+
+	var n CL_uint
+	status := CLGetPlatformIDs(0, nil, &n)
+	if status == CL_PLATFORM_NOT_FOUND_KHR {
+		return nil, nil
+	}
+	if status != CL_SUCCESS {
+		return nil, statusError(status, "CLGetPlatformIDs")
+	}
+	if n == 0 {
+		return nil, nil
+	}
+
+	ids := make([]CL_platform_id, n)
+	status = CLGetPlatformIDs(n, ids, nil)
+	if status != CL_SUCCESS {
+		return nil, statusError(status, "CLGetPlatformIDs")
+	}
+	platforms := make([]Platform, n)
+	for i, id := range ids {
+		platforms[i] = Platform{id}
+	}
+	return platforms, nil
+}
+
+// ID returns the raw platform id.
+func (p Platform) ID() CL_platform_id {
+	return p.id
+}
+
+// Info returns a string platform parameter such as CL_PLATFORM_NAME.
+func (p Platform) Info(param CL_platform_info) (string, error) {
+	var size CL_size_t
+	status := CLGetPlatformInfo(p.id, param, 0, nil, &size)
+	if status != CL_SUCCESS {
+		return "", statusError(status, "CLGetPlatformInfo")
+	}
+
+	var info interface{}
+	status = CLGetPlatformInfo(p.id, param, size, &info, nil)
+	if status != CL_SUCCESS {
+		return "", statusError(status, "CLGetPlatformInfo")
+	}
+	s, _ := info.(string)
+	return s, nil
+}
+
+// Devices returns the devices of the platform matching the device type.  A
+// platform without such devices has none rather than an error.
+func (p Platform) Devices(deviceType CL_device_type) ([]Device, error) {
+	var n CL_uint
+	status := CLGetDeviceIDs(p.id, deviceType, 0, nil, &n)
+	if status == CL_DEVICE_NOT_FOUND || (status == CL_SUCCESS && n == 0) {
+		return nil, nil
+	}
+	if status != CL_SUCCESS {
+		return nil, statusError(status, "CLGetDeviceIDs")
+	}
+
+	ids := make([]CL_device_id, n)
+	status = CLGetDeviceIDs(p.id, deviceType, n, ids, nil)
+	if status != CL_SUCCESS {
+		return nil, statusError(status, "CLGetDeviceIDs")
+	}
+	devices := make([]Device, n)
+	for i, id := range ids {
+		devices[i] = Device{id}
+	}
+	return devices, nil
+}
+
+// Device is an OpenCL device.
+type Device struct {
+	id CL_device_id
+}
+
+// ID returns the raw device id.
+func (d Device) ID() CL_device_id {
+	return d.id
+}
+
+// info returns a device parameter as returned by the raw binding.
+func (d Device) info(param CL_device_info) (interface{}, error) {
+	var size CL_size_t
+	status := CLGetDeviceInfo(d.id, param, 0, nil, &size)
+	if status != CL_SUCCESS {
+		return nil, statusError(status, "CLGetDeviceInfo")
+	}
+
+	var info interface{}
+	status = CLGetDeviceInfo(d.id, param, size, &info, nil)
+	if status != CL_SUCCESS {
+		return nil, statusError(status, "CLGetDeviceInfo")
+	}
+	return info, nil
+}
+
+// InfoString returns a string device parameter such as CL_DEVICE_NAME.
+func (d Device) InfoString(param CL_device_info) (string, error) {
+	info, err := d.info(param)
+	if err != nil {
+		return "", err
+	}
+	s, ok := info.(string)
+	if !ok {
+		return "", fmt.Errorf("Device parameter %#x is not a string",
+			param)
+	}
+	return s, nil
+}
+
+// InfoUint returns a numeric device parameter such as
+// CL_DEVICE_MAX_COMPUTE_UNITS.
+func (d Device) InfoUint(param CL_device_info) (uint64, error) {
+	info, err := d.info(param)
+	if err != nil {
+		return 0, err
+	}
+	switch v := info.(type) {
+	case CL_uint:
+		return uint64(v), nil
+	case CL_size_t:
+		return uint64(v), nil
+	case CL_ulong:
+		return uint64(v), nil
+	}
+	return 0, fmt.Errorf("Device parameter %#x is not a number", param)
+}
+
+// Type returns the type of the device.
+func (d Device) Type() (CL_device_type, error) {
+	info, err := d.info(CL_DEVICE_TYPE)
+	if err != nil {
+		return 0, err
+	}
+	t, _ := info.(CL_device_type)
+	return t, nil
+}
+
+// deviceIDs returns the raw ids of the devices.
+func deviceIDs(devices []Device) []CL_device_id {
+	ids := make([]CL_device_id, len(devices))
+	for i, d := range devices {
+		ids[i] = d.id
+	}
+	return ids
+}
+
+// Context is an OpenCL context.
+type Context struct {
+	c CL_context
+}
+
+// CreateContext creates a context for the devices.
+func CreateContext(devices ...Device) (*Context, error) {
+	var status CL_int
+	c := CLCreateContext(nil, CL_uint(len(devices)), deviceIDs(devices),
+		nil, nil, &status)
+	if status != CL_SUCCESS {
+		return nil, statusError(status, "CLCreateContext")
+	}
+	return &Context{c}, nil
+}
+
+// Close releases the context.
+func (c *Context) Close() error {
+	if c == nil {
+		return nil
+	}
+	status := CLReleaseContext(c.c)
+	if status != CL_SUCCESS {
+		return statusError(status, "CLReleaseContext")
+	}
+	return nil
+}
+
+// CreateQueue creates a command queue for the device.
+func (c *Context) CreateQueue(device Device, properties CL_command_queue_properties) (*Queue, error) {
+	var status CL_int
+	q := CLCreateCommandQueue(c.c, device.id, properties, &status)
+	if status != CL_SUCCESS {
+		return nil, statusError(status, "CLCreateCommandQueue")
+	}
+	return &Queue{q}, nil
+}
+
+// CreateBuffer creates a buffer of size bytes.
+func (c *Context) CreateBuffer(flags CL_mem_flags, size int) (*Buffer, error) {
+	var status CL_int
+	m := CLCreateBuffer(c.c, flags, CL_size_t(size), nil, &status)
+	if status != CL_SUCCESS {
+		return nil, statusError(status, "CLCreateBuffer")
+	}
+	return &Buffer{m, size}, nil
+}
+
+// CreateProgramWithSource creates a program from the source.
+func (c *Context) CreateProgramWithSource(source []byte) (*Program, error) {
+	var status CL_int
+	p := CLCreateProgramWithSource(c.c, 1, [][]byte{source},
+		[]CL_size_t{CL_size_t(len(source))}, &status)
+	if status != CL_SUCCESS {
+		return nil, statusError(status, "CLCreateProgramWithSource")
+	}
+	return &Program{p}, nil
+}
+
+// CreateProgramWithBinary creates a program for the device from a binary
+// previously returned by Program.Binary.
+func (c *Context) CreateProgramWithBinary(device Device, binary []byte) (*Program, error) {
+	var status CL_int
+	binaryStatus := make([]CL_int, 1)
+	p := CLCreateProgramWithBinary(c.c, 1, []CL_device_id{device.id},
+		[]CL_size_t{CL_size_t(len(binary))}, [][]byte{binary},
+		binaryStatus, &status)
+	if status != CL_SUCCESS {
+		return nil, statusError(status, "CLCreateProgramWithBinary")
+	}
+	if binaryStatus[0] != CL_SUCCESS {
+		CLReleaseProgram(p)
+		return nil, statusError(binaryStatus[0],
+			"CLCreateProgramWithBinary")
+	}
+	return &Program{p}, nil
+}
+
+// Queue is an OpenCL command queue.
+type Queue struct {
+	q CL_command_queue
+}
+
+// Close releases the queue.
+func (q *Queue) Close() error {
+	if q == nil {
+		return nil
+	}
+	status := CLReleaseCommandQueue(q.q)
+	if status != CL_SUCCESS {
+		return statusError(status, "CLReleaseCommandQueue")
+	}
+	return nil
+}
+
+// Flush issues the queued commands to the device.
+func (q *Queue) Flush() error {
+	status := CLFlush(q.q)
+	if status != CL_SUCCESS {
+		return statusError(status, "CLFlush")
+	}
+	return nil
+}
+
+// Finish waits for every queued command to complete.
+func (q *Queue) Finish() error {
+	status := CLFinish(q.q)
+	if status != CL_SUCCESS {
+		return statusError(status, "CLFinish")
+	}
+	return nil
+}
+
+// clBool converts a bool to an OpenCL boolean.
+func clBool(b bool) CL_bool {
+	if b {
+		return CL_TRUE
+	}
+	return CL_FALSE
+}
+
+// EnqueueWriteBuffer queues writing src to the buffer at the word offset.
+// Unless the write is blocking, src must not be changed or freed until the
+// returned event completes.  The event must be closed.
+func (q *Queue) EnqueueWriteBuffer(b *Buffer, blocking bool, offset int, src *HostMem) (*Event, error) {
+	var event CL_event
+	status := CLEnqueueWriteBuffer(q.q, b.m, clBool(blocking),
+		CL_size_t(offset*4), CL_size_t(src.words*4), src.p, 0, nil,
+		&event)
+	if status != CL_SUCCESS {
+		return nil, statusError(status, "CLEnqueueWriteBuffer")
+	}
+	return &Event{event}, nil
+}
+
+// EnqueueReadBuffer queues reading the buffer at the word offset into dst.
+// Unless the read is blocking, dst must not be used or freed until the
+// returned event completes.  The event must be closed.
+func (q *Queue) EnqueueReadBuffer(b *Buffer, blocking bool, offset int, dst *HostMem) (*Event, error) {
+	var event CL_event
+	status := CLEnqueueReadBuffer(q.q, b.m, clBool(blocking),
+		CL_size_t(offset*4), CL_size_t(dst.words*4), dst.p, 0, nil,
+		&event)
+	if status != CL_SUCCESS {
+		return nil, statusError(status, "CLEnqueueReadBuffer")
+	}
+	return &Event{event}, nil
+}
+
+// EnqueueKernel queues a one dimensional run of the kernel over globalSize
+// work items starting at offset, in work groups of localSize items.  The
+// returned event must be closed.
+func (q *Queue) EnqueueKernel(k *Kernel, offset, globalSize, localSize int) (*Event, error) {
+	var globalWorkOffset []CL_size_t
+	if offset != 0 {
+		globalWorkOffset = []CL_size_t{CL_size_t(offset)}
+	}
+	globalWorkSize := []CL_size_t{CL_size_t(globalSize)}
+	localWorkSize := []CL_size_t{CL_size_t(localSize)}
+
+	var event CL_event
+	status := CLEnqueueNDRangeKernel(q.q, k.k, 1, globalWorkOffset,
+		globalWorkSize, localWorkSize, 0, nil, &event)
+	if status != CL_SUCCESS {
+		return nil, statusError(status, "CLEnqueueNDRangeKernel")
+	}
+	return &Event{event}, nil
+}
+
+// Event tracks the completion of a queued command.
+type Event struct {
+	e CL_event
+}
+
+// Wait waits for the command to complete.
+func (e *Event) Wait() error {
+	status := CLWaitForEvents(1, []CL_event{e.e})
+	if status != CL_SUCCESS {
+		return statusError(status, "CLWaitForEvents")
+	}
+	return nil
+}
+
+// Close releases the event.
+func (e *Event) Close() error {
+	if e == nil {
+		return nil
+	}
+	status := CLReleaseEvent(e.e)
+	if status != CL_SUCCESS {
+		return statusError(status, "CLReleaseEvent")
+	}
+	return nil
+}
+
+// Buffer is an OpenCL memory buffer.
+type Buffer struct {
+	m    CL_mem
+	size int
+}
+
+// Size returns the size of the buffer in bytes.
+func (b *Buffer) Size() int {
+	return b.size
+}
+
+// Close releases the buffer.
+func (b *Buffer) Close() error {
+	if b == nil {
+		return nil
+	}
+	status := CLReleaseMemObject(b.m)
+	if status != CL_SUCCESS {
+		return statusError(status, "CLReleaseMemObject")
+	}
+	return nil
+}
+
+// Program is an OpenCL program.
+type Program struct {
+	p CL_program
+}
+
+// Build builds the program for the devices with the compiler options.  The
+// build log of a failed build is available from BuildLog.
+func (p *Program) Build(devices []Device, options string) error {
+	status := CLBuildProgram(p.p, CL_uint(len(devices)),
+		deviceIDs(devices), []byte(options), nil, nil)
+	if status != CL_SUCCESS {
+		return statusError(status, "CLBuildProgram")
+	}
+	return nil
+}
+
+// BuildLog returns the log of the last build for the device.
+func (p *Program) BuildLog(device Device) (string, error) {
+	var size CL_size_t
+	status := CLGetProgramBuildInfo(p.p, device.id, CL_PROGRAM_BUILD_LOG,
+		0, nil, &size)
+	if status != CL_SUCCESS {
+		return "", statusError(status, "CLGetProgramBuildInfo")
+	}
+
+	var info interface{}
+	status = CLGetProgramBuildInfo(p.p, device.id, CL_PROGRAM_BUILD_LOG,
+		size, &info, nil)
+	if status != CL_SUCCESS {
+		return "", statusError(status, "CLGetProgramBuildInfo")
+	}
+	return fmt.Sprintf("%v", info), nil
+}
+
+// Binary returns the binary of a program built for a single device.
+func (p *Program) Binary() ([]byte, error) {
+	var info interface{}
+	status := CLGetProgramInfo(p.p, CL_PROGRAM_BINARY_SIZES,
+		CL_size_t(unsafe.Sizeof(CL_size_t(0))), &info, nil)
+	if status != CL_SUCCESS {
+		return nil, statusError(status, "CLGetProgramInfo")
+	}
+	sizes, ok := info.([]CL_size_t)
+	if !ok || len(sizes) != 1 || sizes[0] == 0 {
+		return nil, fmt.Errorf("The program has no binary")
+	}
+
+	binaries := [][]byte{make([]byte, sizes[0])}
+	info = binaries
+	status = CLGetProgramInfo(p.p, CL_PROGRAM_BINARIES,
+		CL_size_t(unsafe.Sizeof(uintptr(0))), &info, nil)
+	if status != CL_SUCCESS {
+		return nil, statusError(status, "CLGetProgramInfo")
+	}
+	return binaries[0], nil
+}
+
+// CreateKernel creates the kernel with the given function name.
+func (p *Program) CreateKernel(name string) (*Kernel, error) {
+	var status CL_int
+	k := CLCreateKernel(p.p, []byte(name), &status)
+	if status != CL_SUCCESS {
+		return nil, statusError(status, "CLCreateKernel")
+	}
+	return &Kernel{k}, nil
+}
+
+// Close releases the program.
+func (p *Program) Close() error {
+	if p == nil {
+		return nil
+	}
+	status := CLReleaseProgram(p.p)
+	if status != CL_SUCCESS {
+		return statusError(status, "CLReleaseProgram")
+	}
+	return nil
+}
+
+// Kernel is an OpenCL kernel.
+type Kernel struct {
+	k CL_kernel
+}
+
+// SetArgUint32 sets the kernel argument at index to a uint.
+func (k *Kernel) SetArgUint32(index int, v uint32) error {
+	status := CLSetKernelArg(k.k, CL_uint(index),
+		CL_size_t(unsafe.Sizeof(v)), unsafe.Pointer(&v))
+	if status != CL_SUCCESS {
+		return statusError(status, "CLSetKernelArg")
+	}
+	return nil
+}
+
+// SetArgBuffer sets the kernel argument at index to a buffer.
+func (k *Kernel) SetArgBuffer(index int, b *Buffer) error {
+	m := b.m
+	status := CLSetKernelArg(k.k, CL_uint(index),
+		CL_size_t(unsafe.Sizeof(m)), unsafe.Pointer(&m))
+	if status != CL_SUCCESS {
+		return statusError(status, "CLSetKernelArg")
+	}
+	return nil
+}
+
+// Close releases the kernel.
+func (k *Kernel) Close() error {
+	if k == nil {
+		return nil
+	}
+	status := CLReleaseKernel(k.k)
+	if status != CL_SUCCESS {
+		return statusError(status, "CLReleaseKernel")
+	}
+	return nil
+}
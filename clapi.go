@@ -0,0 +1,223 @@
+// Copyright (c) 2026 The Decred developers
+
+package main
+
+import (
+	"github.com/decred/gominer/cl"
+)
+
+// The OpenCL worker only talks to its device through the interfaces below, so
+// that it runs on top of either the OpenCL library or the in-memory fake the
+// tests use, in clfake_test.go.  They cover the part of the API the worker
+// needs for a single device, which is why contexts, programs and kernels do
+// not take devices.
+
+// clDeviceAPI is an OpenCL device.
+type clDeviceAPI interface {
+	// Name returns the name of the device.
+	Name() string
+
+	// DriverVersion and PlatformVersion return the versions of the
+	// driver and the platform, which identify the compiler a kernel
+	// binary was built with.
+	DriverVersion() string
+	PlatformVersion() string
+
+	// CreateContext creates a context for the device.
+	CreateContext() (clContextAPI, error)
+}
+
+// clContextAPI is an OpenCL context of a single device.
+type clContextAPI interface {
+	CreateQueue() (clQueueAPI, error)
+	CreateBuffer(size int) (clBufferAPI, error)
+	CreateHostMem(words int) (clHostMemAPI, error)
+	CreateProgramWithSource(source []byte) (clProgramAPI, error)
+	CreateProgramWithBinary(binary []byte) (clProgramAPI, error)
+	Close() error
+}
+
+// clQueueAPI is an OpenCL command queue.  The returned events must be closed.
+type clQueueAPI interface {
+	EnqueueWriteBuffer(b clBufferAPI, blocking bool, offset int, src clHostMemAPI) (clEventAPI, error)
+	EnqueueReadBuffer(b clBufferAPI, blocking bool, offset int, dst clHostMemAPI) (clEventAPI, error)
+	EnqueueKernel(k clKernelAPI, offset, globalSize, localSize int) (clEventAPI, error)
+	Flush() error
+	Finish() error
+	Close() error
+}
+
+// clBufferAPI is an OpenCL memory buffer.
+type clBufferAPI interface {
+	Close() error
+}
+
+// clHostMemAPI is host memory for buffer transfers, which may use it until
+// they complete, after the call queueing them returned.
+type clHostMemAPI interface {
+	Words() []uint32
+	Close() error
+}
+
+// clProgramAPI is an OpenCL program.
+type clProgramAPI interface {
+	Build(options string) error
+	BuildLog() (string, error)
+	Binary() ([]byte, error)
+	CreateKernel(name string) (clKernelAPI, error)
+	Close() error
+}
+
+// clKernelAPI is an OpenCL kernel.
+type clKernelAPI interface {
+	SetArgUint32(index int, v uint32) error
+	SetArgBuffer(index int, b clBufferAPI) error
+	Close() error
+}
+
+// clEventAPI is the completion of an enqueued command.
+type clEventAPI interface {
+	Wait() error
+	Close() error
+}
+
+// libCLDevice implements clDeviceAPI with the OpenCL library.
+type libCLDevice struct {
+	platform cl.Platform
+	device   cl.Device
+}
+
+// Name returns the name of the device.
+func (d libCLDevice) Name() string {
+	return getDeviceInfo(d.device, cl.CL_DEVICE_NAME, "CL_DEVICE_NAME")
+}
+
+// DriverVersion returns the version of the driver of the device.
+func (d libCLDevice) DriverVersion() string {
+	return getDeviceInfo(d.device, cl.CL_DRIVER_VERSION,
+		"CL_DRIVER_VERSION")
+}
+
+// PlatformVersion returns the version of the platform of the device.
+func (d libCLDevice) PlatformVersion() string {
+	return getPlatformInfo(d.platform, cl.CL_PLATFORM_VERSION,
+		"CL_PLATFORM_VERSION")
+}
+
+// CreateContext creates a context for the device.
+func (d libCLDevice) CreateContext() (clContextAPI, error) {
+	c, err := cl.CreateContext(d.device)
+	if err != nil {
+		return nil, err
+	}
+	return libCLContext{c, d.device}, nil
+}
+
+// libCLContext implements clContextAPI with the OpenCL library.
+type libCLContext struct {
+	*cl.Context
+	device cl.Device
+}
+
+func (c libCLContext) CreateQueue() (clQueueAPI, error) {
+	q, err := c.Context.CreateQueue(c.device, 0)
+	if err != nil {
+		return nil, err
+	}
+	return libCLQueue{q}, nil
+}
+
+func (c libCLContext) CreateBuffer(size int) (clBufferAPI, error) {
+	b, err := c.Context.CreateBuffer(cl.CL_MEM_READ_WRITE, size)
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func (c libCLContext) CreateHostMem(words int) (clHostMemAPI, error) {
+	h, err := cl.NewHostMem(words)
+	if err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+func (c libCLContext) CreateProgramWithSource(source []byte) (clProgramAPI, error) {
+	p, err := c.Context.CreateProgramWithSource(source)
+	if err != nil {
+		return nil, err
+	}
+	return libCLProgram{p, c.device}, nil
+}
+
+func (c libCLContext) CreateProgramWithBinary(binary []byte) (clProgramAPI, error) {
+	p, err := c.Context.CreateProgramWithBinary(c.device, binary)
+	if err != nil {
+		return nil, err
+	}
+	return libCLProgram{p, c.device}, nil
+}
+
+// libCLQueue implements clQueueAPI with the OpenCL library.
+type libCLQueue struct {
+	*cl.Queue
+}
+
+func (q libCLQueue) EnqueueWriteBuffer(b clBufferAPI, blocking bool, offset int, src clHostMemAPI) (clEventAPI, error) {
+	e, err := q.Queue.EnqueueWriteBuffer(b.(*cl.Buffer), blocking, offset,
+		src.(*cl.HostMem))
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (q libCLQueue) EnqueueReadBuffer(b clBufferAPI, blocking bool, offset int, dst clHostMemAPI) (clEventAPI, error) {
+	e, err := q.Queue.EnqueueReadBuffer(b.(*cl.Buffer), blocking, offset,
+		dst.(*cl.HostMem))
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (q libCLQueue) EnqueueKernel(k clKernelAPI, offset, globalSize, localSize int) (clEventAPI, error) {
+	e, err := q.Queue.EnqueueKernel(k.(libCLKernel).Kernel, offset,
+		globalSize, localSize)
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// libCLProgram implements clProgramAPI with the OpenCL library.
+type libCLProgram struct {
+	*cl.Program
+	device cl.Device
+}
+
+func (p libCLProgram) Build(options string) error {
+	return p.Program.Build([]cl.Device{p.device}, options)
+}
+
+func (p libCLProgram) BuildLog() (string, error) {
+	return p.Program.BuildLog(p.device)
+}
+
+func (p libCLProgram) CreateKernel(name string) (clKernelAPI, error) {
+	k, err := p.Program.CreateKernel(name)
+	if err != nil {
+		return nil, err
+	}
+	return libCLKernel{k}, nil
+}
+
+// libCLKernel implements clKernelAPI with the OpenCL library.
+type libCLKernel struct {
+	*cl.Kernel
+}
+
+func (k libCLKernel) SetArgBuffer(index int, b clBufferAPI) error {
+	return k.Kernel.SetArgBuffer(index, b.(*cl.Buffer))
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2026 The Decred developers
+
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// defaultKernelCacheDirname is the name of the directory in the home directory
+// compiled kernels are cached in.
+const defaultKernelCacheDirname = "kernelcache"
+
+var kernelCacheDir = filepath.Join(minerHomeDir, defaultKernelCacheDirname)
+
+// A cached program binary is stored in a file named after its cache key.  The
+// file starts with the key and the SHA-256 of the binary so that files that
+// were renamed, truncated or otherwise damaged are detected and rebuilt.
+const programCacheHeaderSize = 2 * sha256.Size
+
+// programCacheKey returns the key the binary of a program built for the
+// device from the source with the compiler options is cached under.  A new
+// driver, source or compiler options result in a new key, which leaves the old
+// entry unused.
+func (w *clWorker) programCacheKey(source []byte, compilerOptions string) [sha256.Size]byte {
+	h := sha256.New()
+	fmt.Fprintf(h, "device %s\x00", w.deviceName)
+	fmt.Fprintf(h, "driver %s\x00", w.device.DriverVersion())
+	fmt.Fprintf(h, "platform %s\x00", w.device.PlatformVersion())
+	fmt.Fprintf(h, "source %x\x00", sha256.Sum256(source))
+	fmt.Fprintf(h, "options %s\x00", compilerOptions)
+
+	var key [sha256.Size]byte
+	copy(key[:], h.Sum(nil))
+	return key
+}
+
+// programCacheFile returns the path of the cache entry with the given key.
+func programCacheFile(key [sha256.Size]byte) string {
+	return filepath.Join(kernelCacheDir, hex.EncodeToString(key[:])+".bin")
+}
+
+// loadCachedProgram creates and builds the program from the binary cached
+// under key.  It returns false when there is no usable binary, removing cache
+// entries the device does not accept so that they get rebuilt.
+func (w *clWorker) loadCachedProgram(key [sha256.Size]byte, compilerOptions string) (clProgramAPI, bool) {
+	if cfg.NoKernelCache {
+		return nil, false
+	}
+
+	path := programCacheFile(key)
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			minrLog.Warnf("Unable to read cached kernel: %v", err)
+		}
+		return nil, false
+	}
+
+	// rebuild removes the entry, logging why it is rebuilt.
+	rebuild := func(reason string) (clProgramAPI, bool) {
+		minrLog.Infof("%s: rebuilding cached kernel %s: %s", w.deviceName,
+			filepath.Base(path), reason)
+		os.Remove(path)
+		return nil, false
+	}
+
+	if len(b) <= programCacheHeaderSize ||
+		!bytes.Equal(b[:sha256.Size], key[:]) {
+		return rebuild("mismatched cache entry")
+	}
+	binary := b[programCacheHeaderSize:]
+	sum := sha256.Sum256(binary)
+	if !bytes.Equal(b[sha256.Size:programCacheHeaderSize], sum[:]) {
+		return rebuild("corrupt cache entry")
+	}
+
+	program, err := w.context.CreateProgramWithBinary(binary)
+	if err != nil {
+		return rebuild(err.Error())
+	}
+
+	// Binaries still have to be built, which is quick.
+	err = program.Build(compilerOptions)
+	if err != nil {
+		program.Close()
+		return rebuild(err.Error())
+	}
+
+	minrLog.Debugf("%s: loaded cached kernel %s", w.deviceName,
+		filepath.Base(path))
+	return program, true
+}
+
+// cacheProgram saves the binary of the program under key.  Failing to do so
+// only costs a rebuild on the next start, so errors are merely logged.
+func (w *clWorker) cacheProgram(key [sha256.Size]byte, program clProgramAPI) {
+	if cfg.NoKernelCache {
+		return
+	}
+
+	err := func() error {
+		binary, err := program.Binary()
+		if err != nil {
+			return err
+		}
+		err = os.MkdirAll(kernelCacheDir, 0700)
+		if err != nil {
+			return err
+		}
+
+		sum := sha256.Sum256(binary)
+		b := make([]byte, 0, programCacheHeaderSize+len(binary))
+		b = append(b, key[:]...)
+		b = append(b, sum[:]...)
+		b = append(b, binary...)
+
+		// Write to a temporary file first so that other miners
+		// starting at the same time never see a partial entry.
+		f, err := ioutil.TempFile(kernelCacheDir, "tmp")
+		if err != nil {
+			return err
+		}
+		_, err = f.Write(b)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err == nil {
+			err = os.Rename(f.Name(), programCacheFile(key))
+		}
+		if err != nil {
+			os.Remove(f.Name())
+		}
+		return err
+	}()
+	if err != nil {
+		minrLog.Warnf("%s: unable to cache kernel: %v", w.deviceName, err)
+		return
+	}
+	minrLog.Debugf("%s: cached kernel %s", w.deviceName,
+		filepath.Base(programCacheFile(key)))
+}
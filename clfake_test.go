@@ -0,0 +1,392 @@
+// Copyright (c) 2026 The Decred developers
+
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/decred/gominer/blake256"
+	"github.com/decred/gominer/cl"
+)
+
+// fakeCLDevice implements clDeviceAPI in memory so the OpenCL worker can run
+// without the OpenCL library.  Commands run when they are enqueued, which is
+// allowed for in order queues, and the search kernel is run in Go with the
+// same argument layout and output format as the real kernels:
+//
+//	arg 0:      the output buffer, a found count followed by the nonces
+//	args 1..8:  the midstate
+//	args 9..20: the final block words 0 to 12, skipping nonce0Word
+//
+// The final block words after the header are the padding, which the kernels
+// have built in.
+type fakeCLDevice struct {
+	name string
+
+	// kernelErr, when set, is returned by the enqueued kernels, by the
+	// next failures of them when failures is set and by all of them
+	// otherwise.
+	kernelErr error
+	failures  int
+
+	// candidateBits, when set, is the number of leading zero bits of the
+	// final hash word that make a nonce a candidate instead of all 32,
+	// so that searches find many candidates.
+	candidateBits int
+
+	// open counts the objects created and not yet closed.
+	open int
+
+	// runs counts the kernel runs.
+	runs int
+}
+
+// newFakeCLDevice returns a fake OpenCL device with the given name.
+func newFakeCLDevice(name string) *fakeCLDevice {
+	return &fakeCLDevice{name: name}
+}
+
+// candidateMask returns the bits of the final hash word that are zero for the
+// nonces the kernels report.
+func (d *fakeCLDevice) candidateMask() uint32 {
+	bits := d.candidateBits
+	if bits == 0 {
+		bits = 32
+	}
+	return ^uint32(0) << uint(32-bits)
+}
+
+// fakeCLError returns the error the library returns for a status.
+func fakeCLError(status cl.CL_int, function string) error {
+	return &cl.Error{
+		Code:     status,
+		Name:     cl.ErrorName(status),
+		Function: function,
+	}
+}
+
+// Name returns the name of the device.
+func (d *fakeCLDevice) Name() string {
+	return d.name
+}
+
+// DriverVersion returns the version of the fake driver.
+func (d *fakeCLDevice) DriverVersion() string {
+	return "fake"
+}
+
+// PlatformVersion returns the version of the fake platform.
+func (d *fakeCLDevice) PlatformVersion() string {
+	return "OpenCL 1.2 fake"
+}
+
+// CreateContext creates a context for the device.
+func (d *fakeCLDevice) CreateContext() (clContextAPI, error) {
+	return &fakeCLContext{d.object()}, nil
+}
+
+// fakeCLObject is the part shared by every fake object.
+type fakeCLObject struct {
+	device *fakeCLDevice
+	closed bool
+}
+
+// Close releases the object.
+func (o *fakeCLObject) Close() error {
+	if o.closed {
+		return fmt.Errorf("Object released twice")
+	}
+	o.closed = true
+	o.device.open--
+	return nil
+}
+
+// object returns a new object of the device.
+func (d *fakeCLDevice) object() fakeCLObject {
+	d.open++
+	return fakeCLObject{device: d}
+}
+
+// fakeCLContext implements clContextAPI for fakeCLDevice.
+type fakeCLContext struct {
+	fakeCLObject
+}
+
+func (c *fakeCLContext) CreateQueue() (clQueueAPI, error) {
+	return &fakeCLQueue{c.device.object()}, nil
+}
+
+func (c *fakeCLContext) CreateBuffer(size int) (clBufferAPI, error) {
+	if size <= 0 || size%4 != 0 {
+		return nil, fakeCLError(cl.CL_INVALID_BUFFER_SIZE,
+			"CLCreateBuffer")
+	}
+	return &fakeCLBuffer{c.device.object(), make([]uint32, size/4)}, nil
+}
+
+func (c *fakeCLContext) CreateProgramWithSource(source []byte) (clProgramAPI, error) {
+	return &fakeCLProgram{fakeCLObject: c.device.object(), source: source}, nil
+}
+
+// fakeCLBinaryPrefix starts the binaries of fake programs, which hold their
+// source.
+var fakeCLBinaryPrefix = []byte("fake opencl binary\x00")
+
+func (c *fakeCLContext) CreateProgramWithBinary(binary []byte) (clProgramAPI, error) {
+	if !bytes.HasPrefix(binary, fakeCLBinaryPrefix) {
+		return nil, fakeCLError(cl.CL_INVALID_BINARY,
+			"CLCreateProgramWithBinary")
+	}
+	return &fakeCLProgram{
+		fakeCLObject: c.device.object(),
+		source:       binary[len(fakeCLBinaryPrefix):],
+	}, nil
+}
+
+// fakeCLBuffer implements clBufferAPI for fakeCLDevice.
+type fakeCLBuffer struct {
+	fakeCLObject
+	data []uint32
+}
+
+func (c *fakeCLContext) CreateHostMem(words int) (clHostMemAPI, error) {
+	if words <= 0 {
+		return nil, fmt.Errorf("Invalid host memory size of %d words",
+			words)
+	}
+	return &fakeCLHostMem{c.device.object(), make([]uint32, words)}, nil
+}
+
+// fakeCLHostMem implements clHostMemAPI for fakeCLDevice.
+type fakeCLHostMem struct {
+	fakeCLObject
+	words []uint32
+}
+
+func (h *fakeCLHostMem) Words() []uint32 {
+	return h.words
+}
+
+// fakeCLProgram implements clProgramAPI for fakeCLDevice.
+type fakeCLProgram struct {
+	fakeCLObject
+	source   []byte
+	built    bool
+	worksize int
+}
+
+// worksizeOption matches the work size define the kernels are built with.
+var worksizeOption = regexp.MustCompile(`-D WORKSIZE=(\d+)`)
+
+func (p *fakeCLProgram) Build(options string) error {
+	m := worksizeOption.FindStringSubmatch(options)
+	if m == nil {
+		return fakeCLError(cl.CL_BUILD_PROGRAM_FAILURE, "CLBuildProgram")
+	}
+	p.worksize, _ = strconv.Atoi(m[1])
+	p.built = true
+	return nil
+}
+
+func (p *fakeCLProgram) BuildLog() (string, error) {
+	if !p.built {
+		return "error: WORKSIZE is not defined", nil
+	}
+	return "", nil
+}
+
+func (p *fakeCLProgram) Binary() ([]byte, error) {
+	if !p.built {
+		return nil, fakeCLError(cl.CL_INVALID_PROGRAM_EXECUTABLE,
+			"CLGetProgramInfo")
+	}
+	return append(append([]byte{}, fakeCLBinaryPrefix...), p.source...),
+		nil
+}
+
+func (p *fakeCLProgram) CreateKernel(name string) (clKernelAPI, error) {
+	if !p.built {
+		return nil, fakeCLError(cl.CL_INVALID_PROGRAM_EXECUTABLE,
+			"CLCreateKernel")
+	}
+	if name != "search" {
+		return nil, fakeCLError(cl.CL_INVALID_KERNEL_NAME,
+			"CLCreateKernel")
+	}
+
+	// Kernels built from the BLAKE3 kernel search with BLAKE3 and all
+	// others with BLAKE-256.
+	algo := blake256Pow
+	if bytes.Equal(p.source, []byte(embeddedKernels["blake3"])) {
+		algo = blake3Pow
+	}
+	return &fakeCLKernel{
+		fakeCLObject: p.device.object(),
+		algo:         algo,
+		worksize:     p.worksize,
+	}, nil
+}
+
+// fakeCLKernelArgs is the number of arguments of the search kernel.
+const fakeCLKernelArgs = 21
+
+// fakeCLKernel implements clKernelAPI for fakeCLDevice.
+type fakeCLKernel struct {
+	fakeCLObject
+	algo     PowAlgorithm
+	worksize int
+	output   *fakeCLBuffer
+	args     [fakeCLKernelArgs]uint32
+	set      [fakeCLKernelArgs]bool
+}
+
+func (k *fakeCLKernel) SetArgUint32(index int, v uint32) error {
+	if index < 1 || index >= fakeCLKernelArgs {
+		return fakeCLError(cl.CL_INVALID_ARG_INDEX, "CLSetKernelArg")
+	}
+	k.args[index] = v
+	k.set[index] = true
+	return nil
+}
+
+func (k *fakeCLKernel) SetArgBuffer(index int, b clBufferAPI) error {
+	if index != 0 {
+		return fakeCLError(cl.CL_INVALID_ARG_VALUE, "CLSetKernelArg")
+	}
+	k.output = b.(*fakeCLBuffer)
+	k.set[0] = true
+	return nil
+}
+
+// work returns the midstate and the final block set with the arguments.
+func (k *fakeCLKernel) work() (midstate [8]uint32, lastBlock [16]uint32) {
+	copy(midstate[:], k.args[1:9])
+	word := 0
+	for _, v := range k.args[9:] {
+		if word == nonce0Word {
+			word++
+		}
+		lastBlock[word] = v
+		word++
+	}
+
+	// The padding only depends on the header length.
+	padding := k.algo.LastBlock(make([]byte, len(Work{}.Data)))
+	copy(lastBlock[word:], padding[word:])
+	return midstate, lastBlock
+}
+
+// run searches globalSize nonces starting at offset like the real kernels,
+// counting the nonces whose final hash word is zero, or has the bits of the
+// candidate mask of the device zero, in the first word of the output buffer
+// and storing them after it.
+func (k *fakeCLKernel) run(offset, globalSize int) {
+	midstate, lastBlock := k.work()
+	output := k.output.data
+	mask := k.device.candidateMask()
+	for i := 0; i < globalSize; i++ {
+		nonce := uint32(offset + i)
+
+		var h [8]uint32
+		if k.algo == blake256Pow {
+			var block [blake256.BlockSize]byte
+			lastBlock[nonce0Word] = nonce
+			for j, v := range lastBlock {
+				binary.BigEndian.PutUint32(block[j*4:], v)
+			}
+			h = midstate
+			blake256.Block(h[:], block[:], headerBits)
+		} else {
+			h = k.algo.FinalBlock(&midstate, &lastBlock, nonce)
+		}
+		if h[7]&mask != 0 {
+			continue
+		}
+
+		// The real kernels write past the end of the buffer when it
+		// is full, which is left out here.
+		output[0]++
+		if int(output[0]) < len(output) {
+			output[output[0]] = nonce
+		}
+	}
+}
+
+// fakeCLQueue implements clQueueAPI for fakeCLDevice.
+type fakeCLQueue struct {
+	fakeCLObject
+}
+
+// fakeCLEvent implements clEventAPI for fakeCLDevice.  Commands complete when
+// they are enqueued, so there is nothing to wait for.
+type fakeCLEvent struct {
+	fakeCLObject
+}
+
+func (e *fakeCLEvent) Wait() error {
+	return nil
+}
+
+// event returns the event of an enqueued command.
+func (q *fakeCLQueue) event() clEventAPI {
+	return &fakeCLEvent{q.device.object()}
+}
+
+func (q *fakeCLQueue) EnqueueWriteBuffer(b clBufferAPI, blocking bool, offset int, h clHostMemAPI) (clEventAPI, error) {
+	buf, src := b.(*fakeCLBuffer), h.Words()
+	if offset < 0 || offset+len(src) > len(buf.data) {
+		return nil, fakeCLError(cl.CL_INVALID_VALUE,
+			"CLEnqueueWriteBuffer")
+	}
+	copy(buf.data[offset:], src)
+	return q.event(), nil
+}
+
+func (q *fakeCLQueue) EnqueueReadBuffer(b clBufferAPI, blocking bool, offset int, h clHostMemAPI) (clEventAPI, error) {
+	buf, dst := b.(*fakeCLBuffer), h.Words()
+	if offset < 0 || offset+len(dst) > len(buf.data) {
+		return nil, fakeCLError(cl.CL_INVALID_VALUE,
+			"CLEnqueueReadBuffer")
+	}
+	copy(dst, buf.data[offset:])
+	return q.event(), nil
+}
+
+func (q *fakeCLQueue) EnqueueKernel(k clKernelAPI, offset, globalSize, localSize int) (clEventAPI, error) {
+	kernel := k.(*fakeCLKernel)
+	for _, set := range kernel.set {
+		if !set {
+			return nil, fakeCLError(cl.CL_INVALID_KERNEL_ARGS,
+				"CLEnqueueNDRangeKernel")
+		}
+	}
+	if localSize != kernel.worksize || globalSize%localSize != 0 {
+		return nil, fakeCLError(cl.CL_INVALID_WORK_GROUP_SIZE,
+			"CLEnqueueNDRangeKernel")
+	}
+	if err := q.device.kernelErr; err != nil {
+		if q.device.failures > 0 {
+			q.device.failures--
+			if q.device.failures == 0 {
+				q.device.kernelErr = nil
+			}
+		}
+		return nil, err
+	}
+
+	kernel.run(offset, globalSize)
+	q.device.runs++
+	return q.event(), nil
+}
+
+func (q *fakeCLQueue) Flush() error {
+	return nil
+}
+
+func (q *fakeCLQueue) Finish() error {
+	return nil
+}
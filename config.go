@@ -8,19 +8,31 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"runtime"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/btcsuite/go-flags"
 	"github.com/decred/dcrutil"
 )
 
 const (
-	defaultConfigFilename = "gominer.conf"
-	defaultLogLevel       = "info"
-	defaultLogDirname     = "logs"
-	defaultLogFilename    = "gominer.log"
-	defaultClKernel       = "blake256.cl"
+	defaultConfigFilename  = "gominer.conf"
+	defaultLogLevel        = "info"
+	defaultLogDirname      = "logs"
+	defaultLogFilename     = "gominer.log"
+	defaultClKernel        = "blake256"
+	defaultClKernelBlake3  = "blake3"
+	defaultBackend         = "opencl"
+	defaultClDeviceType    = "gpu"
+	defaultListFormat      = listFormatTable
+	defaultSimDevices      = 1
+	defaultSimBits         = 16
+	defaultSimStall        = 10 * time.Second
+	defaultPoolWorkTimeout = 5 * time.Minute
+	defaultPoolStrategy    = poolStrategyFailover
+	defaultPoolWeight      = 1
 )
 
 var (
@@ -43,7 +55,10 @@ type config struct {
 	ConfigFile string `short:"C" long:"configfile" description:"Path to configuration file"`
 	LogDir     string `long:"logdir" description:"Directory to log output."`
 	DebugLevel string `short:"d" long:"debuglevel" description:"Logging level for all subsystems {trace, debug, info, warn, error, critical} -- You may also specify <subsystem>=<level>,<subsystem2>=<level>,... to set the log level for individual subsystems -- Use show to list available subsystems"`
-	ClKernel   string `short:"k" long:"kernel" description:"File with cl kernel to use"`
+	ClKernel   string `short:"k" long:"kernel" description:"Embedded cl kernel to use {blake256, blake256-old} or file with the kernel"`
+	DumpKernel string `long:"dumpkernel" description:"Write the source of the named embedded cl kernel to stdout and exit"`
+
+	ClKernelBlake3 string `long:"blake3kernel" description:"Embedded cl kernel to use for blake3 work {blake3} or file with the kernel"`
 
 	// Debugging options
 	Profile    string `long:"profile" description:"Enable HTTP profiling on given port -- NOTE port must be between 1024 and 65536"`
@@ -64,14 +79,57 @@ type config struct {
 
 	TestNet       bool `long:"testnet" description:"Connect to testnet"`
 	SimNet        bool `long:"simnet" description:"Connect to the simulation test network"`
-	TLSSkipVerify bool `long:"skipverify" description:"Do not verify tls certificates (not recommended!)"`
-
-	Intensity int `short:"i" long:"intensity" description:"Intensity."`
+	TLSSkipVerify bool `long:"skipverify" description:"Do not verify tls certificates of the RPC server and pools (not recommended!)"`
+
+	Intensity       int    `short:"i" long:"intensity" description:"Intensity."`
+	Backend         string `long:"backend" description:"Mining backend to use -- Use show to list available backends"`
+	FallbackBackend string `long:"fallbackbackend" description:"Mining backend to use when the one set with --backend is not supported by the system, such as opencl without an OpenCL library (default: exit instead)"`
+	CPUThreads      int    `long:"cputhreads" description:"Number of threads used by the cpu backend (default: number of cores)"`
+	Algo            string `long:"algo" description:"Proof-of-work algorithm to mine {auto, blake256, blake3} -- auto picks it from the activation rules of the network"`
+
+	AutoTune     time.Duration `long:"autotune" description:"Tune the intensity of OpenCL devices so a kernel run takes about this long, e.g. 100ms for desktop use or 1s for dedicated rigs (default: off)"`
+	AutoTuneSave bool          `long:"autotunesave" description:"Save tuned intensities in the home directory and use them instead of tuning again"`
+
+	Blake3Height uint32 `long:"blake3height" description:"Block height BLAKE3 activates at, overriding the default of the network"`
+
+	// OpenCL backend options
+	ClPlatforms      string   `long:"platforms" description:"Comma separated indexes or names of the OpenCL platforms to mine on (default: all)"`
+	ClDeviceType     string   `long:"devicetype" description:"Type of OpenCL devices to mine on {gpu, cpu, accelerator, all}"`
+	ClDevices        string   `long:"devices" description:"Comma separated indexes or name patterns of the OpenCL devices to mine on (default: all)"`
+	ClExcludeDevices string   `long:"excludedevices" description:"Comma separated indexes or name patterns of OpenCL devices not to mine on"`
+	NoKernelCache    bool     `long:"nokernelcache" description:"Always build the OpenCL kernels from source instead of using the binaries cached in the home directory"`
+	DeviceConfig     []string `long:"deviceconfig" description:"Settings of the OpenCL devices matching an index or name pattern, e.g. \"0,*tahiti* intensity=24 worksize=128 kernel=file.cl define=FOO=1\" -- may be given multiple times"`
+	ListDevices      bool     `long:"listdevices" description:"List the OpenCL platforms and devices and exit"`
+	ListFormat       string   `long:"listformat" description:"Format of the device list {table, json}"`
+	ClLibrary        string   `long:"opencllibrary" description:"Path of the OpenCL library to load (default: the one of the system)"`
+
+	// Sim backend options
+	SimDevices     int           `long:"simdevices" description:"Number of devices of the sim backend"`
+	SimHashrate    float64       `long:"simhashrate" description:"Hashrate in hashes per second of each sim device (default: as fast as possible)"`
+	SimBits        int           `long:"simbits" description:"Number of leading zero bits a hash needs for the sim backend to report its nonce"`
+	SimSeed        int64         `long:"simseed" description:"Seed of the random failures of the sim backend"`
+	SimErrorRate   float64       `long:"simerrorrate" description:"Probability of a sim backend search failing with an error"`
+	SimStallRate   float64       `long:"simstallrate" description:"Probability of a sim backend search stalling"`
+	SimStall       time.Duration `long:"simstall" description:"How long a stalled sim backend search hangs"`
+	SimBadHashRate float64       `long:"simbadhashrate" description:"Probability of a sim backend search reporting a nonce that does not solve the work"`
 
 	// Pool related options
-	Pool         string `short:"o" long:"pool" description:"Pool to connect to (e.g.stratum+tcp://pool:port) "`
-	PoolUser     string `short:"m" long:"pooluser" description:"Pool username"`
-	PoolPassword string `short:"n" long:"poolpass" default-mask:"-" description:"Pool password"`
+	Pool            string        `short:"o" long:"pool" description:"Pool to connect to (e.g.stratum+tcp://pool:port, or stratum+ssl://pool:port over TLS) "`
+	PoolUser        string        `short:"m" long:"pooluser" description:"Pool username"`
+	PoolPassword    string        `short:"n" long:"poolpass" default-mask:"-" description:"Pool password"`
+	PoolWeight      int           `long:"poolweight" description:"Weight of the pool set with --pool when balancing"`
+	PoolCert        string        `long:"poolcert" description:"CA or certificate file to verify the TLS certificate of the pool set with --pool against (default: the system roots)"`
+	PoolFingerprint string        `long:"poolfingerprint" description:"SHA-256 fingerprint in hex the TLS certificate of the pool set with --pool must have, which is then trusted without verifying its issuer"`
+	BackupPools     []string      `long:"backuppool" description:"Further pool to fail over to or balance with, with its own credentials, weight and TLS settings, e.g. \"stratum+ssl://pool2:3333 user=worker pass=x weight=2 cert=pool2.pem fingerprint=ab:cd:...\" -- may be given multiple times, in order of priority"`
+	PoolWorkTimeout time.Duration `long:"poolworktimeout" description:"Stop using a pool when it sends no new work for this long"`
+	PoolStrategy    string        `long:"poolstrategy" description:"How to use the pools {failover, balance} -- failover mines on the first pool that works, balance splits the devices between all pools that work by their weights"`
+
+	// clDeviceConfigs holds the parsed DeviceConfig entries.
+	clDeviceConfigs []*clDeviceConfig
+
+	// pools holds the pool set with Pool followed by the parsed
+	// BackupPools entries, in order of priority.
+	pools []*poolConfig
 }
 
 // normalizeAddress returns addr with the passed default port appended if
@@ -224,6 +282,19 @@ func loadConfig() (*config, []string, error) {
 		RPCCert:    defaultRPCCertFile,
 		Intensity:  defaultIntensity,
 		ClKernel:   defaultClKernel,
+		Backend:    defaultBackend,
+		Algo:       autoPowAlgorithm,
+
+		ClKernelBlake3: defaultClKernelBlake3,
+		ClDeviceType:   defaultClDeviceType,
+		ListFormat:     defaultListFormat,
+		SimDevices:     defaultSimDevices,
+		SimBits:        defaultSimBits,
+		SimStall:       defaultSimStall,
+
+		PoolWorkTimeout: defaultPoolWorkTimeout,
+		PoolStrategy:    defaultPoolStrategy,
+		PoolWeight:      defaultPoolWeight,
 	}
 
 	// Create the home directory if it doesn't already exist.
@@ -300,6 +371,178 @@ func loadConfig() (*config, []string, error) {
 		return nil, nil, err
 	}
 
+	if cfg.AutoTune < 0 {
+		err := fmt.Errorf("%s: The auto-tune target may not be "+
+			"negative", funcName)
+		fmt.Fprintln(os.Stderr, err)
+		return nil, nil, err
+	}
+
+	// Special show command to list available backends and exit.
+	if cfg.Backend == "show" {
+		fmt.Println("Supported backends", supportedBackends())
+		os.Exit(0)
+	}
+
+	// Validate the mining backend.
+	if _, ok := backends[cfg.Backend]; !ok {
+		err := fmt.Errorf("%s: The specified backend [%v] is invalid -- "+
+			"supported backends %v", funcName, cfg.Backend,
+			supportedBackends())
+		fmt.Fprintln(os.Stderr, err)
+		return nil, nil, err
+	}
+	if cfg.FallbackBackend != "" {
+		if _, ok := backends[cfg.FallbackBackend]; !ok ||
+			cfg.FallbackBackend == cfg.Backend {
+			err := fmt.Errorf("%s: The specified fallback backend "+
+				"[%v] is invalid -- supported backends %v other "+
+				"than %v", funcName, cfg.FallbackBackend,
+				supportedBackends(), cfg.Backend)
+			fmt.Fprintln(os.Stderr, err)
+			return nil, nil, err
+		}
+	}
+
+	// Default to one cpu mining thread per core.
+	if cfg.CPUThreads == 0 {
+		cfg.CPUThreads = runtime.NumCPU()
+	}
+	if cfg.CPUThreads < 0 || cfg.CPUThreads > maxCPUThreads {
+		err := fmt.Errorf("%s: The number of cpu threads must be "+
+			"between 1 and %d", funcName, maxCPUThreads)
+		fmt.Fprintln(os.Stderr, err)
+		return nil, nil, err
+	}
+
+	// Parse the per-device settings.
+	for _, s := range cfg.DeviceConfig {
+		dc, err := parseCLDeviceConfig(s)
+		if err != nil {
+			err := fmt.Errorf("%s: %v", funcName, err)
+			fmt.Fprintln(os.Stderr, err)
+			return nil, nil, err
+		}
+		cfg.clDeviceConfigs = append(cfg.clDeviceConfigs, dc)
+	}
+
+	// Parse the pools.
+	if cfg.Pool != "" {
+		pc := &poolConfig{
+			url:    cfg.Pool,
+			user:   cfg.PoolUser,
+			pass:   cfg.PoolPassword,
+			weight: cfg.PoolWeight,
+		}
+		if cfg.PoolCert != "" {
+			pc.cert = cleanAndExpandPath(cfg.PoolCert)
+		}
+		if cfg.PoolFingerprint != "" {
+			fp, err := parseFingerprint(cfg.PoolFingerprint)
+			if err != nil {
+				err := fmt.Errorf("%s: %v", funcName, err)
+				fmt.Fprintln(os.Stderr, err)
+				return nil, nil, err
+			}
+			pc.fingerprint = fp
+		}
+		cfg.pools = append(cfg.pools, pc)
+	}
+	if cfg.PoolWeight < 1 {
+		err := fmt.Errorf("%s: The pool weight must be positive",
+			funcName)
+		fmt.Fprintln(os.Stderr, err)
+		return nil, nil, err
+	}
+	if cfg.PoolStrategy != poolStrategyFailover &&
+		cfg.PoolStrategy != poolStrategyBalance {
+		err := fmt.Errorf("%s: The pool strategy [%v] is invalid "+
+			"-- supported strategies [%v %v]", funcName,
+			cfg.PoolStrategy, poolStrategyFailover,
+			poolStrategyBalance)
+		fmt.Fprintln(os.Stderr, err)
+		return nil, nil, err
+	}
+	if len(cfg.BackupPools) > 0 && cfg.Pool == "" {
+		err := fmt.Errorf("%s: Backup pools need a pool set with "+
+			"--pool", funcName)
+		fmt.Fprintln(os.Stderr, err)
+		return nil, nil, err
+	}
+	for _, s := range cfg.BackupPools {
+		pc, err := parsePoolConfig(s)
+		if err != nil {
+			err := fmt.Errorf("%s: %v", funcName, err)
+			fmt.Fprintln(os.Stderr, err)
+			return nil, nil, err
+		}
+		cfg.pools = append(cfg.pools, pc)
+	}
+	for _, pc := range cfg.pools {
+		if _, _, ok := stratumScheme(pc.url); !ok {
+			err := fmt.Errorf("%s: Pool %v is not a stratum+tcp, "+
+				"stratum+ssl or stratum+tls URL", funcName, pc.url)
+			fmt.Fprintln(os.Stderr, err)
+			return nil, nil, err
+		}
+		pc.skipVerify = cfg.TLSSkipVerify
+		_, err := pc.tlsConfig()
+		if err != nil {
+			err := fmt.Errorf("%s: Pool %v: %v", funcName, pc.url,
+				err)
+			fmt.Fprintln(os.Stderr, err)
+			return nil, nil, err
+		}
+	}
+	if cfg.PoolWorkTimeout <= 0 {
+		err := fmt.Errorf("%s: The pool work timeout must be positive",
+			funcName)
+		fmt.Fprintln(os.Stderr, err)
+		return nil, nil, err
+	}
+
+	// Validate the format of the device list.
+	if cfg.ListFormat != listFormatTable && cfg.ListFormat != listFormatJSON {
+		err := fmt.Errorf("%s: The device list format [%v] is invalid "+
+			"-- supported formats [%v %v]", funcName, cfg.ListFormat,
+			listFormatTable, listFormatJSON)
+		fmt.Fprintln(os.Stderr, err)
+		return nil, nil, err
+	}
+
+	// Validate the sim backend options.
+	if cfg.SimDevices < 1 {
+		err := fmt.Errorf("%s: The sim backend needs at least one "+
+			"device", funcName)
+		fmt.Fprintln(os.Stderr, err)
+		return nil, nil, err
+	}
+	if cfg.SimBits < 1 || cfg.SimBits > 32 {
+		err := fmt.Errorf("%s: The number of sim backend bits %v is "+
+			"not within range 1 to 32", funcName, cfg.SimBits)
+		fmt.Fprintln(os.Stderr, err)
+		return nil, nil, err
+	}
+	for _, rate := range []float64{cfg.SimErrorRate, cfg.SimStallRate,
+		cfg.SimBadHashRate} {
+		if rate < 0 || rate > 1 {
+			err := fmt.Errorf("%s: The sim backend failure rate %v "+
+				"is not within range 0 to 1", funcName, rate)
+			fmt.Fprintln(os.Stderr, err)
+			return nil, nil, err
+		}
+	}
+
+	// Validate the proof-of-work algorithm.
+	_, ok := powAlgorithmByName(cfg.Algo)
+	if !ok && cfg.Algo != autoPowAlgorithm {
+		err := fmt.Errorf("%s: The specified algorithm [%v] is invalid "+
+			"-- supported algorithms %v", funcName, cfg.Algo,
+			append([]string{autoPowAlgorithm}, powAlgorithmNames()...))
+		fmt.Fprintln(os.Stderr, err)
+		return nil, nil, err
+	}
+
 	// Special show command to list supported subsystems and exit.
 	if cfg.DebugLevel == "show" {
 		fmt.Println("Supported subsystems", supportedSubsystems())
@@ -0,0 +1,162 @@
+// Copyright (c) 2026 The Decred developers
+
+package main
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/decred/gominer/blake256"
+)
+
+// cpuThreadBatchSize is the number of nonces each cpu thread hashes for every
+// call to Search.  It is kept small enough that new work is picked up about
+// once a second on a typical core.
+const cpuThreadBatchSize = 1 << 20
+
+// maxCPUThreads is the most cpu threads the cpu backend runs, which keeps the
+// batch of all threads together within the uint32 nonce range.
+const maxCPUThreads = 1<<32/cpuThreadBatchSize - 1
+
+func init() {
+	registerBackend("cpu", newCPUBackend)
+}
+
+// cpuBackend is a Backend that mines on the host processor in pure Go.
+type cpuBackend struct{}
+
+func newCPUBackend() (Backend, error) {
+	return &cpuBackend{}, nil
+}
+
+// Name returns the name of the cpu backend.
+func (b *cpuBackend) Name() string {
+	return "cpu"
+}
+
+// Workers returns a single worker that spreads its searches across the
+// configured number of threads.
+func (b *cpuBackend) Workers() ([]Worker, error) {
+	return []Worker{newCPUWorker(cfg.CPUThreads)}, nil
+}
+
+// cpuWorker searches for nonces by hashing the final header block on the cpu
+// using several goroutines, each covering its own slice of the nonce0 range.
+type cpuWorker struct {
+	threads   int
+	algo      PowAlgorithm
+	midstate  [8]uint32
+	lastBlock [16]uint32
+}
+
+func newCPUWorker(threads int) *cpuWorker {
+	return &cpuWorker{threads: threads}
+}
+
+// Name returns a description of the worker.
+func (w *cpuWorker) Name() string {
+	return fmt.Sprintf("CPU (%d threads)", w.threads)
+}
+
+// BatchSize returns the number of nonces hashed by all threads together for
+// each call to Search.
+func (w *cpuWorker) BatchSize() uint32 {
+	return uint32(w.threads) * cpuThreadBatchSize
+}
+
+// SetWork stores the algorithm, the midstate and the final block for the
+// following searches.
+func (w *cpuWorker) SetWork(algo PowAlgorithm, midstate *[8]uint32, lastBlock *[16]uint32) error {
+	w.algo = algo
+	w.midstate = *midstate
+	w.lastBlock = *lastBlock
+	return nil
+}
+
+// Search splits the count nonces starting at start evenly between the threads
+// and returns every nonce whose final hash word is zero.
+func (w *cpuWorker) Search(start, count uint32) ([]uint32, uint64, error) {
+	var (
+		wg         sync.WaitGroup
+		mtx        sync.Mutex
+		candidates []uint32
+	)
+
+	perThread := count / uint32(w.threads)
+	for i := 0; i < w.threads; i++ {
+		from := start + uint32(i)*perThread
+		to := from + perThread
+		if i == w.threads-1 {
+			to = start + count
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var found []uint32
+			if w.algo == blake256Pow {
+				found = w.searchRange(from, to)
+			} else {
+				found = w.searchRangeGeneric(from, to)
+			}
+			if len(found) != 0 {
+				mtx.Lock()
+				candidates = append(candidates, found...)
+				mtx.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return candidates, uint64(count), nil
+}
+
+// searchRange hashes the nonces in [from, to) and returns those whose final
+// hash word is zero, which is the same test the OpenCL kernel performs.  The
+// nonces are hashed eight at a time using the multi-lane block function.
+func (w *cpuWorker) searchRange(from, to uint32) []uint32 {
+	var (
+		found  []uint32
+		nonces [8]uint32
+		out    [8][8]uint32
+	)
+	for remaining := to - from; remaining != 0; {
+		n := uint32(len(nonces))
+		if remaining < n {
+			n = remaining
+		}
+		for i := range nonces {
+			nonces[i] = from + uint32(i)
+		}
+
+		// Only the final block needs to be hashed.  The counter is
+		// the full 180 byte header length in bits.
+		blake256.Block8(&out, &w.midstate, &w.lastBlock, nonce0Word,
+			&nonces, headerBits)
+		for i := uint32(0); i < n; i++ {
+			if out[7][i] == 0 {
+				found = append(found, nonces[i])
+			}
+		}
+
+		from += n
+		remaining -= n
+	}
+	return found
+}
+
+// searchRangeGeneric is searchRange for algorithms without a multi-lane block
+// function.  The nonces are hashed one at a time.
+func (w *cpuWorker) searchRangeGeneric(from, to uint32) []uint32 {
+	var found []uint32
+	for nonce := from; nonce != to; nonce++ {
+		h := w.algo.FinalBlock(&w.midstate, &w.lastBlock, nonce)
+		if h[7] == 0 {
+			found = append(found, nonce)
+		}
+	}
+	return found
+}
+
+// Release is a no-op since the cpu worker holds no external resources.
+func (w *cpuWorker) Release() {}
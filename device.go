@@ -1,82 +1,73 @@
 package main
 
 import (
-	"encoding/binary"
+	"bytes"
 	"encoding/hex"
 	"fmt"
-	"math"
 	"math/big"
-	"os"
-	"unsafe"
+	"sync"
+	"time"
 
 	"github.com/decred/dcrd/blockchain"
 	"github.com/decred/dcrd/chaincfg/chainhash"
-
-	"github.com/decred/gominer/blake256"
-	"github.com/decred/gominer/cl"
+	"github.com/decred/dcrd/wire"
 )
 
 const (
-	outputBufferSize = cl.CL_size_t(64)
-	localWorksize    = 64
-	uint32Size       = cl.CL_size_t(unsafe.Sizeof(cl.CL_uint(0)))
-
 	nonce0Word = 3
 	nonce1Word = 4
 	nonce2Word = 5
-)
-
-var zeroSlice = []cl.CL_uint{cl.CL_uint(0)}
 
-func loadProgramSource(filename string) ([][]byte, []cl.CL_size_t, error) {
-	var program_buffer [1][]byte
-	var program_size [1]cl.CL_size_t
-
-	/* Read each program file and place content into buffer array */
-	program_handle, err := os.Open(filename)
-	if err != nil {
-		return nil, nil, err
-	}
-	defer program_handle.Close()
+	// headerBits is the length of the serialized block header in bits,
+	// which is the counter used when hashing its final block.
+	headerBits = wire.MaxBlockHeaderPayload * 8
 
-	fi, err := program_handle.Stat()
-	if err != nil {
-		return nil, nil, err
-	}
-	program_size[0] = cl.CL_size_t(fi.Size())
-	program_buffer[0] = make([]byte, program_size[0])
-	read_size, err := program_handle.Read(program_buffer[0])
-	if err != nil || cl.CL_size_t(read_size) != program_size[0] {
-		return nil, nil, err
-	}
+	// maxDeviceRetries is the number of times in a row a device is
+	// restarted after a temporary worker error without getting any work
+	// done in between.
+	maxDeviceRetries = 3
+)
 
-	return program_buffer[:], program_size[:], nil
-}
+// deviceRetryDelay is the time a device waits before it is restarted after a
+// temporary worker error.
+var deviceRetryDelay = 5 * time.Second
 
 type Work struct {
 	Data   [192]byte
 	Target [32]byte
+
+	// Algorithm is the proof-of-work algorithm the work is mined with.
+	// Work without one uses the algorithm selected for its header.
+	Algorithm PowAlgorithm
+
+	// Pool is the stratum session the work is from, which its solutions
+	// are submitted to, or nil for work from getwork.  PoolJob is the job
+	// of the pool the work was built from.
+	Pool    *Stratum
+	PoolJob stratumJob
 }
 
 type Device struct {
-	index        int
-	platformID   cl.CL_platform_id
-	deviceID     cl.CL_device_id
-	deviceName   string
-	context      cl.CL_context
-	queue        cl.CL_command_queue
-	outputBuffer cl.CL_mem
-	program      cl.CL_program
-	kernel       cl.CL_kernel
+	index  int
+	worker Worker
+	name   string
 
+	algo      PowAlgorithm
 	midstate  [8]uint32
 	lastBlock [16]uint32
 
 	work     Work
 	newWork  chan *Work
-	workDone chan []byte
+	workDone chan *Work
 	hasWork  bool
 
+	// workGen changes whenever the work is superseded, which makes the
+	// solutions of the searches queued before worthless.
+	workGen uint64
+
+	// statsMtx protects the work done, which the device adds to while
+	// PrintStats reports it.
+	statsMtx      sync.Mutex
 	workDoneEMA   float64
 	workDoneLast  float64
 	workDoneTotal float64
@@ -98,146 +89,166 @@ func hashSmaller(a, b []byte) bool {
 	return false
 }
 
-func clError(status cl.CL_int, f string) error {
-	return fmt.Errorf("%s returned error %s (%d)", f, cl.ERROR_CODES_STRINGS[-status], status)
-}
-
-func NewDevice(index int, platformID cl.CL_platform_id, deviceID cl.CL_device_id, workDone chan []byte) (*Device, error) {
-	d := &Device{
-		index:      index,
-		platformID: platformID,
-		deviceID:   deviceID,
-		deviceName: getDeviceInfo(deviceID, cl.CL_DEVICE_NAME, "CL_DEVICE_NAME"),
-		quit:       make(chan struct{}),
-		newWork:    make(chan *Work, 5),
-		workDone:   workDone,
+// NewDevice returns a new device that mines using the passed worker.
+func NewDevice(index int, worker Worker, workDone chan *Work) *Device {
+	return &Device{
+		index:    index,
+		worker:   worker,
+		name:     worker.Name(),
+		quit:     make(chan struct{}),
+		newWork:  make(chan *Work, 5),
+		workDone: workDone,
 	}
+}
 
-	var status cl.CL_int
+func (d *Device) Release() {
+	d.worker.Release()
+}
 
-	// Create the CL context
-	d.context = cl.CLCreateContext(nil, 1, []cl.CL_device_id{deviceID}, nil, nil, &status)
-	if status != cl.CL_SUCCESS {
-		return nil, clError(status, "CLCreateContext")
-	}
+func (d *Device) updateCurrentWork() {
+	var w *Work
+	for w == nil {
+		if d.hasWork {
+			// If we already have work, we just need to check if
+			// there's new one without blocking if there's not.
+			select {
+			case w = <-d.newWork:
+			default:
+				return
+			}
+		} else {
+			// If we don't have work, we block until we do. We need
+			// to watch for quit events too.
+			select {
+			case w = <-d.newWork:
+			case <-d.quit:
+				return
+			}
+		}
 
-	// Create the command queue
-	d.queue = cl.CLCreateCommandQueue(d.context, deviceID, 0, &status)
-	if status != cl.CL_SUCCESS {
-		return nil, clError(status, "CLCreateCommandQueue")
+		// No work pauses the device until there is work again.
+		if w == nil && d.hasWork {
+			minrLog.Infof("Device #%d: paused", d.index)
+			d.hasWork = false
+		}
 	}
 
-	// Create the output buffer
-	d.outputBuffer = cl.CLCreateBuffer(d.context, cl.CL_MEM_READ_WRITE, uint32Size*outputBufferSize, nil, &status)
-	if status != cl.CL_SUCCESS {
-		return nil, clError(status, "CLCreateBuffer")
+	algo := w.Algorithm
+	if algo == nil {
+		algo = selectPowAlgorithm(w.Data[:])
 	}
-
-	// Load kernel source
-	progSrc, progSize, err := loadProgramSource(cfg.ClKernel)
-	if err != nil {
-		return nil, fmt.Errorf("Could not load kernel source: %v", err)
+	switch {
+	case !d.hasWork:
+		minrLog.Infof("Device #%d: mining with %s", d.index, algo.Name())
+	case algo != d.algo:
+		minrLog.Infof("Device #%d: switching from %s to %s", d.index,
+			d.algo.Name(), algo.Name())
 	}
+	d.algo = algo
 
-	// Create the program
-	d.program = cl.CLCreateProgramWithSource(d.context, 1, progSrc[:], progSize[:], &status)
-	if status != cl.CL_SUCCESS {
-		return nil, clError(status, "CLCreateProgramWithSource")
+	// Solutions of earlier work are stale once the device was paused or
+	// the work is for another block or from another pool.
+	if !d.hasWork || w.Pool != d.work.Pool ||
+		!bytes.Equal(w.Data[4:36], d.work.Data[4:36]) {
+		d.workGen++
 	}
 
-	// Build the program for the device
-	compilerOptions := ""
-	compilerOptions += fmt.Sprintf(" -D WORKSIZE=%d", localWorksize)
-	status = cl.CLBuildProgram(d.program, 1, []cl.CL_device_id{deviceID}, []byte(compilerOptions), nil, nil)
-	if status != cl.CL_SUCCESS {
-		err = clError(status, "CLBuildProgram")
-
-		// Something went wrong! Print what it is.
-		var logSize cl.CL_size_t
-		status = cl.CLGetProgramBuildInfo(d.program, deviceID, cl.CL_PROGRAM_BUILD_LOG, 0, nil, &logSize)
-		if status != cl.CL_SUCCESS {
-			minrLog.Errorf("Could not obtain compilation error log: %v", clError(status, "CLGetProgramBuildInfo"))
-		}
-		var program_log interface{}
-		status = cl.CLGetProgramBuildInfo(d.program, deviceID, cl.CL_PROGRAM_BUILD_LOG, logSize, &program_log, nil)
-		if status != cl.CL_SUCCESS {
-			minrLog.Errorf("Could not obtain compilation error log: %v", clError(status, "CLGetProgramBuildInfo"))
-		}
-		minrLog.Errorf("%s\n", program_log)
+	d.hasWork = true
 
-		return nil, err
-	}
+	d.work = *w
 
-	// Create the kernel
-	d.kernel = cl.CLCreateKernel(d.program, []byte("search"), &status)
-	if status != cl.CL_SUCCESS {
-		return nil, clError(status, "CLCreateKernel")
-	}
+	// Set nonce2
+	d.algo.ByteOrder().PutUint32(d.work.Data[128+4*nonce2Word:], uint32(d.index))
 
-	return d, nil
-}
+	// Hash the two first blocks
+	d.midstate = d.algo.Midstate(d.work.Data[:])
 
-func (d *Device) Release() {
-	cl.CLReleaseKernel(d.kernel)
-	cl.CLReleaseProgram(d.program)
-	cl.CLReleaseCommandQueue(d.queue)
-	cl.CLReleaseMemObject(d.outputBuffer)
-	cl.CLReleaseContext(d.context)
+	// Convert the next block to uint32 array.
+	d.lastBlock = d.algo.LastBlock(d.work.Data[:])
 }
 
-func (d *Device) updateCurrentWork() {
-	var w *Work
-	if d.hasWork {
-		// If we already have work, we just need to check if there's new one
-		// without blocking if there's not.
-		select {
-		case w = <-d.newWork:
-		default:
+// Run mines with the device until it is stopped or fails.  Temporary worker
+// errors, such as the hardware running out of resources, restart the device
+// after a delay unless it keeps failing without getting any work done.
+func (d *Device) Run() {
+	retries := 0
+	for {
+		workDone := d.totalWorkDone()
+		err := d.runDevice()
+		if err == nil {
 			return
 		}
-	} else {
-		// If we don't have work, we block until we do. We need to watch for
-		// quit events too.
+		if d.totalWorkDone() > workDone {
+			retries = 0
+		}
+		if !isTemporary(err) || retries >= maxDeviceRetries {
+			minrLog.Errorf("Error on device: %v", err)
+			return
+		}
+		retries++
+
+		minrLog.Warnf("Device #%d: %v -- retrying in %v", d.index, err,
+			deviceRetryDelay)
 		select {
-		case w = <-d.newWork:
 		case <-d.quit:
 			return
+		case <-time.After(deviceRetryDelay):
 		}
 	}
+}
 
-	d.hasWork = true
+func (d *Device) runDevice() error {
+	minrLog.Infof("Started device #%d: %s", d.index, d.name)
+	if w, ok := d.worker.(AsyncWorker); ok {
+		return d.runDeviceAsync(w)
+	}
 
-	d.work = *w
+	for {
+		d.updateCurrentWork()
 
-	// Set nonce2
-	binary.BigEndian.PutUint32(d.work.Data[128+4*nonce2Word:], uint32(d.index))
+		select {
+		case <-d.quit:
+			return nil
+		default:
+		}
 
-	// Reset the hash state
-	copy(d.midstate[:], blake256.IV256[:])
+		// Increment nonce1
+		d.lastBlock[nonce1Word]++
 
-	// Hash the two first blocks
-	blake256.Block(d.midstate[:], d.work.Data[0:64], 512)
-	blake256.Block(d.midstate[:], d.work.Data[64:128], 1024)
+		err := d.worker.SetWork(d.algo, &d.midstate, &d.lastBlock)
+		if err != nil {
+			return err
+		}
 
-	// Convert the next block to uint32 array.
-	for i := 0; i < 16; i++ {
-		d.lastBlock[i] = binary.BigEndian.Uint32(d.work.Data[128+i*4 : 132+i*4])
+		candidates, hashes, err := d.worker.Search(0, d.worker.BatchSize())
+		if err != nil {
+			return err
+		}
+
+		for _, nonce0 := range candidates {
+			minrLog.Debugf("Found candidate: %d", nonce0)
+			d.foundCandidate(&d.work, d.algo, d.lastBlock[nonce1Word], nonce0)
+		}
+
+		d.addWorkDone(hashes)
 	}
 }
 
-func (d *Device) Run() {
-	err := d.runDevice()
-	if err != nil {
-		minrLog.Errorf("Error on device: %v", err)
-	}
+// queuedSearch is the work a search queued on an AsyncWorker was started
+// with, which the candidates it finds belong to even if the device has moved
+// on to new work by the time they are known.  The candidates are dropped when
+// that work was superseded in the meantime.
+type queuedSearch struct {
+	work    Work
+	algo    PowAlgorithm
+	nonce1  uint32
+	workGen uint64
 }
 
-func (d *Device) runDevice() error {
-	minrLog.Infof("Started GPU #%d: %s", d.index, d.deviceName)
-	outputData := make([]uint32, outputBufferSize)
-	globalWorksize := math.Exp2(float64(cfg.Intensity))
-	minrLog.Debugf("Intensity %v", cfg.Intensity)
-	var status cl.CL_int
+// runDeviceAsync is runDevice for workers with a search queue.  It keeps the
+// queue full, queueing the next search before waiting for the oldest one.
+func (d *Device) runDeviceAsync(w AsyncWorker) error {
+	queued := make([]queuedSearch, 0, w.QueueSize())
 	for {
 		d.updateCurrentWork()
 
@@ -250,84 +261,57 @@ func (d *Device) runDevice() error {
 		// Increment nonce1
 		d.lastBlock[nonce1Word]++
 
-		// arg 0: pointer to the buffer
-		obuf := d.outputBuffer
-		status = cl.CLSetKernelArg(d.kernel, 0, cl.CL_size_t(unsafe.Sizeof(obuf)), unsafe.Pointer(&obuf))
-		if status != cl.CL_SUCCESS {
-			return clError(status, "CLSetKernelArg")
+		err := w.SetWork(d.algo, &d.midstate, &d.lastBlock)
+		if err != nil {
+			return err
 		}
 
-		// args 1..8: midstate
-		for i := 0; i < 8; i++ {
-			ms := d.midstate[i]
-			status = cl.CLSetKernelArg(d.kernel, cl.CL_uint(i+1), uint32Size, unsafe.Pointer(&ms))
-			if status != cl.CL_SUCCESS {
-				return clError(status, "CLSetKernelArg")
-			}
+		err = w.Enqueue(0, w.BatchSize())
+		if err != nil {
+			return err
 		}
-
-		// args 9..20: lastBlock except nonce
-		i2 := 0
-		for i := 0; i < 12; i++ {
-			if i2 == nonce0Word {
-				i2++
-			}
-			lb := d.lastBlock[i2]
-			status = cl.CLSetKernelArg(d.kernel, cl.CL_uint(i+9), uint32Size, unsafe.Pointer(&lb))
-			if status != cl.CL_SUCCESS {
-				return clError(status, "CLSetKernelArg")
-			}
-			i2++
+		queued = append(queued, queuedSearch{
+			work:    d.work,
+			algo:    d.algo,
+			nonce1:  d.lastBlock[nonce1Word],
+			workGen: d.workGen,
+		})
+		if len(queued) < w.QueueSize() {
+			continue
 		}
 
-		// Clear the found count from the buffer
-		status = cl.CLEnqueueWriteBuffer(d.queue, d.outputBuffer, cl.CL_FALSE, 0, uint32Size, unsafe.Pointer(&zeroSlice[0]), 0, nil, nil)
-		if status != cl.CL_SUCCESS {
-			return clError(status, "CLEnqueueWriteBuffer")
+		candidates, hashes, err := w.Wait()
+		if err != nil {
+			return err
 		}
-
-		// Execute the kernel
-		var globalWorkSize [1]cl.CL_size_t
-		globalWorkSize[0] = cl.CL_size_t(globalWorksize)
-		var localWorkSize [1]cl.CL_size_t
-		localWorkSize[0] = localWorksize
-		status = cl.CLEnqueueNDRangeKernel(d.queue, d.kernel, 1, nil, globalWorkSize[:], localWorkSize[:], 0, nil, nil)
-		if status != cl.CL_SUCCESS {
-			return clError(status, "CLEnqueueNDRangeKernel")
+		search := &queued[0]
+		if search.workGen != d.workGen && len(candidates) > 0 {
+			minrLog.Debugf("Device #%d: dropping %d candidates of "+
+				"stale work", d.index, len(candidates))
+			candidates = nil
 		}
-
-		// Read the output buffer
-		cl.CLEnqueueReadBuffer(d.queue, d.outputBuffer, cl.CL_TRUE, 0, uint32Size*outputBufferSize, unsafe.Pointer(&outputData[0]), 0, nil, nil)
-		if status != cl.CL_SUCCESS {
-			return clError(status, "CLEnqueueReadBuffer")
-		}
-
-		for i := uint32(0); i < outputData[0]; i++ {
-			minrLog.Debugf("Found candidate: %d", outputData[i+1])
-			d.foundCandidate(d.lastBlock[nonce1Word], outputData[i+1])
+		for _, nonce0 := range candidates {
+			minrLog.Debugf("Found candidate: %d", nonce0)
+			d.foundCandidate(&search.work, search.algo, search.nonce1,
+				nonce0)
 		}
+		queued = append(queued[:0], queued[1:]...)
 
-		d.workDoneLast += globalWorksize
-		d.workDoneTotal += globalWorksize
+		d.addWorkDone(hashes)
 	}
 }
 
-func (d *Device) foundCandidate(nonce1 uint32, nonce0 uint32) {
+func (d *Device) foundCandidate(work *Work, algo PowAlgorithm, nonce1 uint32, nonce0 uint32) {
 	// Construct the final block header
-	data := make([]byte, 192)
-	copy(data, d.work.Data[:])
-	binary.BigEndian.PutUint32(data[128+4*nonce1Word:], nonce1)
-	binary.BigEndian.PutUint32(data[128+4*nonce0Word:], nonce0)
-
-	// Perform the final hash block to get the hash
-	var state [8]uint32
-	copy(state[:], d.midstate[:])
-	blake256.Block(state[:], data[128:192], 1440)
-
-	var hash [32]byte
-	for i := 0; i < 8; i++ {
-		binary.BigEndian.PutUint32(hash[i*4:], state[i])
-	}
+	solved := *work
+	data := solved.Data[:]
+	order := algo.ByteOrder()
+	order.PutUint32(data[128+4*nonce1Word:], nonce1)
+	order.PutUint32(data[128+4*nonce0Word:], nonce0)
+
+	// Hash the full header to verify the candidate independently of the
+	// midstate the worker was given.
+	hash := algo.Hash(data[:wire.MaxBlockHeaderPayload])
 
 	newHash, err := chainhash.NewHashFromStr(hex.EncodeToString(reverse(hash[:])))
 	if err != nil {
@@ -335,20 +319,28 @@ func (d *Device) foundCandidate(nonce1 uint32, nonce0 uint32) {
 	}
 	hashNum := blockchain.ShaHashToBig(newHash)
 	target := new(big.Int)
-	target.SetString(hex.EncodeToString(reverse(d.work.Target[:])), 16)
+	target.SetString(hex.EncodeToString(reverse(work.Target[:])), 16)
 	if hashNum.Cmp(target) > 0 {
-		minrLog.Infof("Hash %s below target %s", hex.EncodeToString(reverse(hash[:])), hex.EncodeToString(reverse(d.work.Target[:])))
+		minrLog.Infof("Hash %s below target %s", hex.EncodeToString(reverse(hash[:])), hex.EncodeToString(reverse(work.Target[:])))
 
 	} else {
 		minrLog.Infof("Found hash!!  %s", hex.EncodeToString(hash[:]))
-		d.workDone <- data
+		select {
+		case d.workDone <- &solved:
+		case <-d.quit:
+		}
 	}
 }
 
 func (d *Device) Stop() {
 	close(d.quit)
+	if w, ok := d.worker.(interruptibleWorker); ok {
+		w.Interrupt()
+	}
 }
 
+// SetWork hands the device new work to mine.  Nil work pauses the device
+// until it is given work again.
 func (d *Device) SetWork(w *Work) {
 	d.newWork <- w
 }
@@ -367,36 +359,30 @@ func formatHashrate(h float64) string {
 	return fmt.Sprintf("%.1f GH/s", h)
 }
 
-func getDeviceInfo(id cl.CL_device_id,
-	name cl.CL_device_info,
-	str string) string {
-
-	var errNum cl.CL_int
-	var paramValueSize cl.CL_size_t
-
-	errNum = cl.CLGetDeviceInfo(id, name, 0, nil, &paramValueSize)
-
-	if errNum != cl.CL_SUCCESS {
-		return fmt.Sprintf("Failed to find OpenCL device info %s.\n", str)
-	}
-
-	var info interface{}
-	errNum = cl.CLGetDeviceInfo(id, name, paramValueSize, &info, nil)
-	if errNum != cl.CL_SUCCESS {
-		return fmt.Sprintf("Failed to find OpenCL device info %s.\n", str)
-	}
-
-	strinfo := fmt.Sprintf("%v", info)
+// addWorkDone adds the hashes of a search to the work done by the device.
+func (d *Device) addWorkDone(hashes uint64) {
+	d.statsMtx.Lock()
+	d.workDoneLast += float64(hashes)
+	d.workDoneTotal += float64(hashes)
+	d.statsMtx.Unlock()
+}
 
-	return strinfo
+// totalWorkDone returns the number of hashes the device has done.
+func (d *Device) totalWorkDone() float64 {
+	d.statsMtx.Lock()
+	defer d.statsMtx.Unlock()
+	return d.workDoneTotal
 }
 
 func (d *Device) PrintStats() {
+	d.statsMtx.Lock()
+	defer d.statsMtx.Unlock()
+
 	alpha := 0.95
 	d.workDoneEMA = d.workDoneEMA*alpha + d.workDoneLast*(1-alpha)
 	d.workDoneLast = 0
 	d.runningTime += 5.0
 
-	minrLog.Infof("GPU #%d: %s, EMA %s avg %s", d.index, d.deviceName,
+	minrLog.Infof("Device #%d: %s, EMA %s avg %s", d.index, d.name,
 		formatHashrate(d.workDoneEMA), formatHashrate(d.workDoneTotal/d.runningTime))
 }
@@ -0,0 +1,112 @@
+// Copyright (c) 2026 The Decred developers
+
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// scriptedWorker is an AsyncWorker whose every search finds nonce 1.  It calls
+// onEnqueue, when set, with the number of searches queued so far, which lets
+// tests hand the device new work at a set point.
+type scriptedWorker struct {
+	queued    int
+	onEnqueue func(n int)
+}
+
+func (w *scriptedWorker) Name() string {
+	return "Scripted worker"
+}
+
+func (w *scriptedWorker) BatchSize() uint32 {
+	return 1
+}
+
+func (w *scriptedWorker) SetWork(PowAlgorithm, *[8]uint32, *[16]uint32) error {
+	return nil
+}
+
+func (w *scriptedWorker) Search(start, count uint32) ([]uint32, uint64, error) {
+	return []uint32{1}, uint64(count), nil
+}
+
+func (w *scriptedWorker) Release() {}
+
+func (w *scriptedWorker) QueueSize() int {
+	return 2
+}
+
+func (w *scriptedWorker) Enqueue(start, count uint32) error {
+	w.queued++
+	if w.onEnqueue != nil {
+		w.onEnqueue(w.queued)
+	}
+	return nil
+}
+
+func (w *scriptedWorker) Wait() ([]uint32, uint64, error) {
+	return []uint32{1}, 1, nil
+}
+
+// TestDeviceStaleWork checks that the solutions of queued searches are
+// dropped when the device is paused or mines on another block before they are
+// known, and kept when there is only new work for the same block.
+func TestDeviceStaleWork(t *testing.T) {
+	cfg = &config{Algo: autoPowAlgorithm}
+
+	// The works are told apart by a byte of the stake root.
+	newWork := func(marker byte) *Work {
+		w := &Work{}
+		for i := range w.Target {
+			w.Target[i] = 0xff
+		}
+		w.Data[100] = marker
+		return w
+	}
+	newBlock := newWork(2)
+	newBlock.Data[4] = 1
+
+	tests := []struct {
+		name  string
+		pause bool
+		next  *Work
+		want  byte
+	}{
+		{"new work for the same block", false, newWork(2), 1},
+		{"new block", false, newBlock, 2},
+		{"pause", true, newWork(2), 2},
+	}
+	for _, test := range tests {
+		solved := make(chan *Work, 10)
+		var d *Device
+		w := &scriptedWorker{onEnqueue: func(n int) {
+			if n != 1 {
+				return
+			}
+			if test.pause {
+				d.SetWork(nil)
+			}
+			d.SetWork(test.next)
+		}}
+		d = NewDevice(0, w, solved)
+		done := make(chan struct{})
+		go func() {
+			d.Run()
+			close(done)
+		}()
+		d.SetWork(newWork(1))
+
+		select {
+		case work := <-solved:
+			if got := work.Data[100]; got != test.want {
+				t.Errorf("%s: first solution is of work %d, "+
+					"want %d", test.name, got, test.want)
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatalf("%s: no solution", test.name)
+		}
+		d.Stop()
+		<-done
+	}
+}
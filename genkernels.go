@@ -0,0 +1,58 @@
+// Copyright (c) 2026 The Decred developers
+
+// +build ignore
+
+// genkernels generates kernelsources.go, which embeds the OpenCL kernels in
+// the miner.  Run it with go generate after changing any of the kernels.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"strings"
+)
+
+// kernels lists the embedded kernels by name along with their files.
+var kernels = []struct {
+	name string
+	file string
+}{
+	{"blake256", "blake256.cl"},
+	{"blake256-old", "blake256-old.cl"},
+	{"blake3", "blake3.cl"},
+}
+
+func main() {
+	var b bytes.Buffer
+	fmt.Fprintln(&b, "// Code generated by genkernels.go; DO NOT EDIT.")
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, "package main")
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, "// embeddedKernels maps the names of the embedded kernels "+
+		"to their source.")
+	fmt.Fprintln(&b, "var embeddedKernels = map[string]string{")
+	for _, k := range kernels {
+		src, err := ioutil.ReadFile(k.file)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if bytes.IndexByte(src, '`') != -1 {
+			log.Fatalf("%s contains a backquote", k.file)
+		}
+		s := strings.Replace(string(src), "\r", "", -1)
+		fmt.Fprintf(&b, "\t// %s\n\t%q: `%s`,\n\n", k.file, k.name, s)
+	}
+	fmt.Fprintln(&b, "}")
+
+	out, err := format.Source(b.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	err = ioutil.WriteFile("kernelsources.go", out, 0644)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -178,11 +178,17 @@ func GetWork() (*Work, error) {
 	var w Work
 	copy(w.Data[:], data)
 	copy(w.Target[:], target)
+	w.Algorithm = selectPowAlgorithm(w.Data[:])
 	return &w, nil
 }
 
 // GetPoolWork gets work from a stratum enabled pool
 func GetPoolWork(pool *Stratum) (*Work, error) {
+	// The work is built from the state of the pool at one point in time,
+	// which the listener does not change meanwhile.
+	pool.workMtx.Lock()
+	defer pool.workMtx.Unlock()
+
 	// Get Next work for stratum and mark it as used
 	if pool.PoolWork.NewWork {
 		poolLog.Info("Received new work from pool.")
@@ -268,33 +274,19 @@ func GetWorkSubmit(data []byte) (bool, error) {
 	return res.Result, nil
 }
 
-// GetPoolWorkSubmit sends the result to the stratum enabled pool
-func GetPoolWorkSubmit(data []byte, pool *Stratum) (bool, error) {
-
-	sub, err := pool.PrepSubmit(data)
-	if err != nil {
-		return false, err
-	}
-
-	// json encode
-	m, err := json.Marshal(sub)
+// GetPoolWorkSubmit sends the solved work to the stratum enabled pool it is
+// from, for the job it was built from.
+func GetPoolWorkSubmit(work *Work) (bool, error) {
+	sub, err := work.Pool.PrepSubmit(work.Data[:], work.PoolJob)
 	if err != nil {
 		return false, err
 	}
 
-	// send
-	poolLog.Tracef("> %s", m)
-	_, err = pool.Conn.Write(m)
+	// The pool's verdict is logged when its reply comes in.
+	err = work.Pool.SubmitShare(sub)
 	if err != nil {
 		return false, err
 	}
-	_, err = pool.Conn.Write([]byte("\n"))
-	if err != nil {
-		return false, err
-	}
-	pool.submitted = true
-
-	pool.PoolWork.Work = nil
 
 	return false, nil
 }
@@ -0,0 +1,32 @@
+// Copyright (c) 2026 The Decred developers
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+//go:generate go run genkernels.go
+
+// embeddedKernelNames returns the sorted names of the embedded kernels.
+func embeddedKernelNames() []string {
+	names := make([]string, 0, len(embeddedKernels))
+	for name := range embeddedKernels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// dumpKernel writes the source of the named embedded kernel to w.
+func dumpKernel(w io.Writer, name string) error {
+	src, ok := embeddedKernels[name]
+	if !ok {
+		return fmt.Errorf("Unknown kernel %q -- embedded kernels %v",
+			name, embeddedKernelNames())
+	}
+	_, err := io.WriteString(w, src)
+	return err
+}
@@ -0,0 +1,173 @@
+// Copyright (c) 2026 The Decred developers
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/decred/gominer/cl"
+)
+
+// Formats supported by --listformat.
+const (
+	listFormatTable = "table"
+	listFormatJSON  = "json"
+)
+
+// clPlatformListing is the description of a platform printed by
+// --listdevices.
+type clPlatformListing struct {
+	Index   int               `json:"index"`
+	Name    string            `json:"name"`
+	Vendor  string            `json:"vendor"`
+	Version string            `json:"version"`
+	Devices []clDeviceListing `json:"devices"`
+}
+
+// clDeviceListing is the description of a device printed by --listdevices.
+type clDeviceListing struct {
+	Index         int      `json:"index"`
+	Name          string   `json:"name"`
+	Type          string   `json:"type"`
+	Vendor        string   `json:"vendor"`
+	DriverVersion string   `json:"driverversion"`
+	OpenCLVersion string   `json:"openclversion"`
+	ComputeUnits  uint64   `json:"computeunits"`
+	MaxWorkGroup  uint64   `json:"maxworkgroupsize"`
+	GlobalMemory  uint64   `json:"globalmemory"`
+	Extensions    []string `json:"extensions"`
+}
+
+// clDeviceTypeName returns the --devicetype names of the types set in t.
+func clDeviceTypeName(t cl.CL_device_type) string {
+	var names []string
+	for _, name := range clDeviceTypeNames() {
+		if name != "all" && t&clDeviceTypes[name] != 0 {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return "other"
+	}
+	return strings.Join(names, "+")
+}
+
+// getPlatformInfoString returns a platform info, or an empty string if it is
+// not available.  Unlike getPlatformInfo, the listing is left without the
+// info rather than given the failure text as its value.
+func getPlatformInfoString(platform cl.Platform, name cl.CL_platform_info) string {
+	info, err := platform.Info(name)
+	if err != nil {
+		return ""
+	}
+	return info
+}
+
+// getDeviceInfoString returns a device info, or an empty string if it is not
+// available.
+func getDeviceInfoString(device cl.Device, name cl.CL_device_info) string {
+	info, err := device.InfoString(name)
+	if err != nil {
+		return ""
+	}
+	return info
+}
+
+// getDeviceInfoUint returns a numeric device info, or zero if it is not
+// available.
+func getDeviceInfoUint(device cl.Device, name cl.CL_device_info) uint64 {
+	info, err := device.InfoUint(name)
+	if err != nil {
+		return 0
+	}
+	return info
+}
+
+// clListing describes every OpenCL platform and device.
+func clListing() ([]clPlatformListing, error) {
+	platforms, err := enumerateCLPlatforms()
+	if err != nil {
+		return nil, err
+	}
+
+	listing := make([]clPlatformListing, 0, len(platforms))
+	for _, p := range platforms {
+		pl := clPlatformListing{
+			Index: p.index,
+			Name:  p.name,
+			Vendor: getPlatformInfoString(p.platform,
+				cl.CL_PLATFORM_VENDOR),
+			Version: getPlatformInfoString(p.platform,
+				cl.CL_PLATFORM_VERSION),
+			Devices: make([]clDeviceListing, 0, len(p.devices)),
+		}
+		for _, d := range p.devices {
+			pl.Devices = append(pl.Devices, clDeviceListing{
+				Index: d.index,
+				Name:  d.name,
+				Type:  clDeviceTypeName(d.deviceType),
+				Vendor: getDeviceInfoString(d.device,
+					cl.CL_DEVICE_VENDOR),
+				DriverVersion: getDeviceInfoString(d.device,
+					cl.CL_DRIVER_VERSION),
+				OpenCLVersion: getDeviceInfoString(d.device,
+					cl.CL_DEVICE_VERSION),
+				ComputeUnits: getDeviceInfoUint(d.device,
+					cl.CL_DEVICE_MAX_COMPUTE_UNITS),
+				MaxWorkGroup: getDeviceInfoUint(d.device,
+					cl.CL_DEVICE_MAX_WORK_GROUP_SIZE),
+				GlobalMemory: getDeviceInfoUint(d.device,
+					cl.CL_DEVICE_GLOBAL_MEM_SIZE),
+				Extensions: strings.Fields(getDeviceInfoString(
+					d.device, cl.CL_DEVICE_EXTENSIONS)),
+			})
+		}
+		listing = append(listing, pl)
+	}
+	return listing, nil
+}
+
+// listCLDevices writes the description of every OpenCL platform and device to
+// w in the given format.
+func listCLDevices(w io.Writer, format string) error {
+	listing, err := clListing()
+	if err != nil {
+		return err
+	}
+
+	if format == listFormatJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(listing)
+	}
+
+	for i, p := range listing {
+		if i != 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "Platform #%d: %s (%s, %s)\n", p.Index, p.Name,
+			p.Vendor, p.Version)
+		if len(p.Devices) == 0 {
+			fmt.Fprintln(w, "  No devices")
+			continue
+		}
+
+		tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(tw, "  #\tNAME\tTYPE\tVENDOR\tDRIVER\tOPENCL\t"+
+			"CU\tWG SIZE\tMEMORY\tEXTENSIONS")
+		for _, d := range p.Devices {
+			fmt.Fprintf(tw, "  %d\t%s\t%s\t%s\t%s\t%s\t%d\t%d\t%dMiB\t%s\n",
+				d.Index, d.Name, d.Type, d.Vendor, d.DriverVersion,
+				d.OpenCLVersion, d.ComputeUnits, d.MaxWorkGroup,
+				d.GlobalMemory>>20, strings.Join(d.Extensions, " "))
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
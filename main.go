@@ -1,6 +1,7 @@
 package main
 
 import (
+	"fmt"
 	"net"
 	"net/http"
 	"os"
@@ -24,6 +25,25 @@ func gominerMain() error {
 	cfg = tcfg
 	defer backendLog.Flush()
 
+	// Write out an embedded kernel and exit if requested.
+	if cfg.DumpKernel != "" {
+		err := dumpKernel(os.Stdout, cfg.DumpKernel)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Unable to dump kernel: %v\n", err)
+		}
+		return err
+	}
+
+	// List the OpenCL devices and exit if requested.  This is done before
+	// anything is logged so the list can be parsed by scripts.
+	if cfg.ListDevices {
+		err := listCLDevices(os.Stdout, cfg.ListFormat)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Unable to list devices: %v\n", err)
+		}
+		return err
+	}
+
 	// Show version at startup.
 	mainLog.Infof("Version %s", version())
 
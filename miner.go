@@ -4,81 +4,51 @@ import (
 	"fmt"
 	"sync"
 	"time"
-
-	"github.com/decred/gominer/cl"
 )
 
-func getCLPlatforms() ([]cl.CL_platform_id, error) {
-	var numPlatforms cl.CL_uint
-	status := cl.CLGetPlatformIDs(0, nil, &numPlatforms)
-	if status != cl.CL_SUCCESS {
-		return nil, clError(status, "CLGetPlatformIDs")
-	}
-	platforms := make([]cl.CL_platform_id, numPlatforms)
-	status = cl.CLGetPlatformIDs(numPlatforms, platforms, nil)
-	if status != cl.CL_SUCCESS {
-		return nil, clError(status, "CLGetPlatformIDs")
-	}
-	return platforms, nil
-}
-
-// getCLDevices returns the list of devices for the given platform.
-func getCLDevices(platform cl.CL_platform_id) ([]cl.CL_device_id, error) {
-	var numDevices cl.CL_uint
-	status := cl.CLGetDeviceIDs(platform, cl.CL_DEVICE_TYPE_GPU, 0, nil, &numDevices)
-	if status != cl.CL_SUCCESS {
-		return nil, clError(status, "CLGetDeviceIDs")
-	}
-	devices := make([]cl.CL_device_id, numDevices)
-	status = cl.CLGetDeviceIDs(platform, cl.CL_DEVICE_TYPE_ALL, numDevices, devices, nil)
-	if status != cl.CL_SUCCESS {
-		return nil, clError(status, "CLGetDeviceIDs")
-	}
-	return devices, nil
-}
-
 type Miner struct {
 	devices          []*Device
-	workDone         chan []byte
+	workDone         chan *Work
 	quit             chan struct{}
 	needsWorkRefresh chan struct{}
 	wg               sync.WaitGroup
-	pool             *Stratum
+	pools            *poolManager
 }
 
 func NewMiner() (*Miner, error) {
 	m := &Miner{
-		workDone:         make(chan []byte, 10),
+		workDone:         make(chan *Work, 10),
 		quit:             make(chan struct{}),
 		needsWorkRefresh: make(chan struct{}),
 	}
 
-	// If needed, start pool code.
-	if cfg.Pool != "" && !cfg.Benchmark {
-		s, err := StratumConn(cfg.Pool, cfg.PoolUser, cfg.PoolPassword)
-		if err != nil {
-			return nil, err
-		}
-		m.pool = s
-	}
-
-	platformIDs, err := getCLPlatforms()
+	backend, err := newBackendWithFallback(cfg.Backend, cfg.FallbackBackend)
 	if err != nil {
-		return nil, fmt.Errorf("Could not get CL platforms: %v", err)
+		return nil, err
 	}
-	platformID := platformIDs[0]
-	deviceIDs, err := getCLDevices(platformID)
+	workers, err := backend.Workers()
 	if err != nil {
-		return nil, fmt.Errorf("Could not get CL devices for platform: %v", err)
+		return nil, err
+	}
+	if len(workers) == 0 {
+		return nil, fmt.Errorf("No devices found for backend %v",
+			backend.Name())
 	}
 
-	m.devices = make([]*Device, len(deviceIDs))
-	for i, deviceID := range deviceIDs {
-		var err error
-		m.devices[i], err = NewDevice(i, platformID, deviceID, m.workDone)
+	m.devices = make([]*Device, len(workers))
+	for i, worker := range workers {
+		m.devices[i] = NewDevice(i, worker, m.workDone)
+	}
+
+	// If needed, start pool code.
+	if len(cfg.pools) > 0 && !cfg.Benchmark {
+		pools := newPoolManager(cfg.pools, cfg.PoolWorkTimeout,
+			cfg.PoolStrategy, len(m.devices))
+		err := pools.start()
 		if err != nil {
 			return nil, err
 		}
+		m.pools = pools
 	}
 
 	return m, nil
@@ -91,38 +61,55 @@ func (m *Miner) workSubmitThread() {
 		select {
 		case <-m.quit:
 			return
-		case data := <-m.workDone:
+		case work := <-m.workDone:
+			data := work.Data[:]
 			// Only use that is we are not using a pool.
-			if m.pool == nil {
+			if m.pools == nil {
 				accepted, err := GetWorkSubmit(data)
 				if err != nil {
 					minrLog.Errorf("Error submitting work: %v", err)
 				} else {
 					minrLog.Errorf("Submitted work successfully: %v", accepted)
-					m.needsWorkRefresh <- struct{}{}
+					m.refreshWork()
 				}
 			} else {
-				accepted, err := GetPoolWorkSubmit(data, m.pool)
+				// Solutions go to the pool the work is from,
+				// even if the device mines for another one by
+				// now.
+				accepted, err := GetPoolWorkSubmit(work)
 				if err != nil {
 					minrLog.Errorf("Error submitting work to pool: %v", err)
 				} else {
 					minrLog.Errorf("Submitted work to pool successfully: %v", accepted)
-					m.needsWorkRefresh <- struct{}{}
+					m.refreshWork()
 				}
 			}
 		}
 	}
 }
 
+// refreshWork wakes up the work refresh thread, unless the miner is stopping.
+func (m *Miner) refreshWork() {
+	select {
+	case m.needsWorkRefresh <- struct{}{}:
+	case <-m.quit:
+	}
+}
+
 func (m *Miner) workRefreshThread() {
 	defer m.wg.Done()
 
 	t := time.NewTicker(time.Second)
 	defer t.Stop()
 
+	// The pool every device mines for and whether it is paused, when
+	// mining on pools.
+	pools := make([]*Stratum, len(m.devices))
+	paused := make([]bool, len(m.devices))
+
 	for {
 		// Only use that is we are not using a pool.
-		if m.pool == nil {
+		if m.pools == nil {
 			work, err := GetWork()
 			if err != nil {
 				minrLog.Errorf("Error in getwork: %v", err)
@@ -132,13 +119,40 @@ func (m *Miner) workRefreshThread() {
 				}
 			}
 		} else {
-			work, err := GetPoolWork(m.pool)
-			if err != nil {
-				minrLog.Errorf("Error in getpoolwork: %v", err)
-			} else {
-				for _, d := range m.devices {
+			// Get the work of every pool a device mines for once.
+			works := make(map[*Stratum]*Work)
+			poolWork := func(pool *Stratum) *Work {
+				if pool == nil || !pool.Connected() {
+					return nil
+				}
+				work, ok := works[pool]
+				if !ok {
+					var err error
+					work, err = GetPoolWork(pool)
+					if err != nil {
+						minrLog.Errorf("Error in "+
+							"getpoolwork: %v", err)
+					}
+					works[pool] = work
+				}
+				return work
+			}
+
+			for i, d := range m.devices {
+				pool := m.pools.PoolFor(i)
+				work := poolWork(pool)
+				switch {
+				case work != nil:
 					d.SetWork(work)
+					paused[i] = false
+				case !paused[i] || pool != pools[i]:
+					// Work from a lost connection or
+					// another pool is of no use, so stop
+					// mining it until there is new work.
+					d.SetWork(nil)
+					paused[i] = true
 				}
+				pools[i] = pool
 			}
 		}
 		select {
@@ -160,6 +174,9 @@ func (m *Miner) printStatsThread() {
 		for _, d := range m.devices {
 			d.PrintStats()
 		}
+		if m.pools != nil {
+			m.pools.PrintStats()
+		}
 
 		select {
 		case <-m.quit:
@@ -188,6 +205,7 @@ func (m *Miner) Run() {
 	if cfg.Benchmark {
 		minrLog.Warn("Running in BENCHMARK mode! No real mining taking place!")
 		work := &Work{}
+		work.Algorithm = selectPowAlgorithm(work.Data[:])
 		for _, d := range m.devices {
 			d.SetWork(work)
 		}
@@ -204,8 +222,11 @@ func (m *Miner) Run() {
 
 func (m *Miner) Stop() {
 	close(m.quit)
+	if m.pools != nil {
+		m.pools.Close()
+	}
+	// The devices are done once Run sees them return.
 	for _, d := range m.devices {
 		d.Stop()
-		m.wg.Done()
 	}
 }
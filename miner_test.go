@@ -0,0 +1,83 @@
+// Copyright (c) 2026 The Decred developers
+
+package main
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// TestMinerPoolSession runs a miner with sim devices against a mock pool and
+// checks that the solutions injected into the devices reach the pool as
+// accepted shares.
+func TestMinerPoolSession(t *testing.T) {
+	// At this difficulty the share target is the largest there is, so
+	// every nonce is a share.
+	pool := newMockPool(t, 1e-10)
+
+	cfg = &config{
+		Algo:            autoPowAlgorithm,
+		Backend:         "sim",
+		SimDevices:      2,
+		SimBits:         32,
+		PoolWorkTimeout: time.Minute,
+		PoolStrategy:    poolStrategyFailover,
+		pools: []*poolConfig{
+			{url: pool.url(), user: "user", pass: "pass", weight: 1},
+		},
+	}
+	m, err := NewMiner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan struct{})
+	go func() {
+		m.Run()
+		close(done)
+	}()
+
+	// Shares carry the nonce as the block header serializes it, and the
+	// extranonce2 of the device that found it, which is its index.  The
+	// jobs of the pool are at height 0.
+	var work Work
+	order := selectPowAlgorithm(work.Data[:]).ByteOrder()
+	want := map[string]string{}
+	for i, d := range m.devices {
+		nonce := uint32(0x1000 + i)
+		d.worker.(*simWorker).InjectSolution(nonce)
+		var b [4]byte
+		order.PutUint32(b[:], nonce)
+		header := uint64(binary.LittleEndian.Uint32(b[:]))
+		order.PutUint32(b[:], uint32(i))
+		want[strconv.FormatUint(header, 16)] = hex.EncodeToString(b[:])
+	}
+	waitFor(t, "shares", func() bool {
+		for _, share := range pool.submitted() {
+			en2, ok := want[share[4]]
+			if !ok {
+				continue
+			}
+			if share[1] != "1" || share[2] != en2 {
+				t.Fatalf("Share %v is not for job 1 with "+
+					"extranonce2 %s", share, en2)
+			}
+			delete(want, share[4])
+		}
+		return len(want) == 0
+	})
+	session := m.pools.PoolFor(0)
+	waitFor(t, "accepted shares", func() bool {
+		accepted, rejected := session.Shares()
+		return accepted >= len(m.devices) && rejected == 0
+	})
+
+	m.Stop()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Miner did not stop")
+	}
+}
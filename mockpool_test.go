@@ -0,0 +1,164 @@
+// Copyright (c) 2026 The Decred developers
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// mockPool is an in-process stratum pool for tests.  It gives every miner
+// that logs in a job at a fixed difficulty and accepts every share it is
+// sent.
+type mockPool struct {
+	ln   net.Listener
+	diff float64
+
+	mtx         sync.Mutex
+	conns       []net.Conn
+	extraNonces []string
+	shares      [][]string
+}
+
+// newMockPool starts a mock pool that hands out work at difficulty diff.  It
+// is stopped when the test ends.
+func newMockPool(t *testing.T, diff float64) *mockPool {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return serveMockPool(t, ln, diff)
+}
+
+// serveMockPool starts a mock pool on the listener.
+func serveMockPool(t *testing.T, ln net.Listener, diff float64) *mockPool {
+	p := &mockPool{ln: ln, diff: diff}
+	go p.accept()
+	t.Cleanup(p.close)
+	return p
+}
+
+// url returns the URL of the pool.
+func (p *mockPool) url() string {
+	return "stratum+tcp://" + p.ln.Addr().String()
+}
+
+// accept serves every connection to the pool with its own extranonce1, which
+// is made of the port of the pool and the number of the connection so that
+// those of different pools differ too.
+func (p *mockPool) accept() {
+	port := p.ln.Addr().(*net.TCPAddr).Port
+	for n := 0; ; n++ {
+		conn, err := p.ln.Accept()
+		if err != nil {
+			return
+		}
+		extraNonce1 := fmt.Sprintf("%04x%04x", port, n)
+		p.mtx.Lock()
+		p.conns = append(p.conns, conn)
+		p.extraNonces = append(p.extraNonces, extraNonce1)
+		p.mtx.Unlock()
+		go p.serve(conn, extraNonce1)
+	}
+}
+
+// serve answers the requests of a miner until the connection is closed.
+func (p *mockPool) serve(conn net.Conn, extraNonce1 string) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		var msg StratumMsg
+		if err := json.Unmarshal([]byte(line), &msg); err != nil {
+			return
+		}
+		switch msg.Method {
+		case "mining.subscribe":
+			fmt.Fprintf(conn, `{"id":%v,"result":[[["mining.set_difficulty","1"],`+
+				`["mining.notify","1"]],"%s",4],"error":null}`+"\n",
+				msg.ID, extraNonce1)
+		case "mining.authorize":
+			fmt.Fprintf(conn, `{"id":%v,"result":true,"error":null}`+"\n",
+				msg.ID)
+			fmt.Fprintf(conn, `{"id":null,"method":"mining.set_difficulty",`+
+				`"params":[%v]}`+"\n", p.diff)
+			p.notify(conn, "1")
+		case "mining.submit":
+			p.mtx.Lock()
+			p.shares = append(p.shares, msg.Params)
+			p.mtx.Unlock()
+			fmt.Fprintf(conn, `{"id":%v,"result":true,"error":null}`+"\n",
+				msg.ID)
+		}
+	}
+}
+
+// notify sends the miner a job with the ID.  The coinbase is all zeros, which
+// puts the block at height 0.
+func (p *mockPool) notify(conn net.Conn, jobID string) {
+	fmt.Fprintf(conn, `{"id":null,"method":"mining.notify","params":`+
+		`["%s","%s","%s","",[],"07000000","1d00ffff","%08x",true]}`+"\n",
+		jobID, strings.Repeat("ab", 32), strings.Repeat("00", 108),
+		time.Now().Unix())
+}
+
+// send sends the message to every miner connected to the pool.
+func (p *mockPool) send(msg string) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	for _, conn := range p.conns {
+		fmt.Fprintln(conn, msg)
+	}
+}
+
+// connections returns the number of times miners connected to the pool.
+func (p *mockPool) connections() int {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	return len(p.conns)
+}
+
+// extraNonce1 returns the extranonce1 of connection n to the pool.
+func (p *mockPool) extraNonce1(n int) string {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	return p.extraNonces[n]
+}
+
+// submitted returns the params of the shares the pool was sent.
+func (p *mockPool) submitted() [][]string {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	return append([][]string(nil), p.shares...)
+}
+
+// close stops the pool and drops its connections.
+func (p *mockPool) close() {
+	p.ln.Close()
+	p.mtx.Lock()
+	for _, conn := range p.conns {
+		conn.Close()
+	}
+	p.mtx.Unlock()
+}
+
+// waitFor fails the test unless cond becomes true within ten seconds.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	for start := time.Now(); time.Since(start) < 10*time.Second; {
+		if cond() {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("Timed out waiting for %s", what)
+}
@@ -0,0 +1,858 @@
+// Copyright (c) 2026 The Decred developers
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/decred/gominer/cl"
+)
+
+const (
+	// outputBufferSize is the number of uint32 words in an output buffer.
+	outputBufferSize = 64
+
+	// numOutputBuffers is the number of searches a worker can have queued
+	// on its device.  With two the next search is queued while the device
+	// still runs the previous one, so it never sits idle while the results
+	// are handled.
+	numOutputBuffers = 2
+
+	// localWorksize is the local work size of devices without one set
+	// with --deviceconfig.
+	localWorksize = 64
+)
+
+func init() {
+	registerBackend("opencl", newCLBackend)
+}
+
+// loadKernelSource returns the source of the named embedded kernel or, if
+// there is no such kernel, of the kernel file.
+func loadKernelSource(kernel string) ([]byte, error) {
+	if src, ok := embeddedKernels[kernel]; ok {
+		return []byte(src), nil
+	}
+	return ioutil.ReadFile(kernel)
+}
+
+func getPlatformInfo(platform cl.Platform,
+	name cl.CL_platform_info,
+	str string) string {
+
+	info, err := platform.Info(name)
+	if err != nil {
+		return fmt.Sprintf("Failed to find OpenCL platform info %s.\n", str)
+	}
+	return info
+}
+
+func getDeviceInfo(device cl.Device,
+	name cl.CL_device_info,
+	str string) string {
+
+	info, err := device.InfoString(name)
+	if err != nil {
+		return fmt.Sprintf("Failed to find OpenCL device info %s.\n", str)
+	}
+	return info
+}
+
+// clDeviceTypes maps the --devicetype names to OpenCL device types.
+var clDeviceTypes = map[string]cl.CL_device_type{
+	"gpu":         cl.CL_DEVICE_TYPE_GPU,
+	"cpu":         cl.CL_DEVICE_TYPE_CPU,
+	"accelerator": cl.CL_DEVICE_TYPE_ACCELERATOR,
+	"all":         cl.CL_DEVICE_TYPE_ALL,
+}
+
+// clDeviceTypeNames returns the sorted names of the OpenCL device types.
+func clDeviceTypeNames() []string {
+	names := make([]string, 0, len(clDeviceTypes))
+	for name := range clDeviceTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// clSelector is a comma separated list of platforms or devices given in the
+// config.  Each entry is either an index or a name pattern, which matches
+// names containing it or matching it as a shell pattern, ignoring case.
+type clSelector []string
+
+func parseCLSelector(s string) clSelector {
+	var sel clSelector
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry != "" {
+			sel = append(sel, entry)
+		}
+	}
+	return sel
+}
+
+// matches returns whether any entry of the selector matches the platform or
+// device with the given index and name.
+func (sel clSelector) matches(index int, name string) bool {
+	name = strings.ToLower(name)
+	for _, entry := range sel {
+		if i, err := strconv.Atoi(entry); err == nil {
+			if i == index {
+				return true
+			}
+			continue
+		}
+		if strings.Contains(name, entry) {
+			return true
+		}
+		if ok, _ := path.Match(entry, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// clPlatform is an OpenCL platform along with all of its devices.
+type clPlatform struct {
+	index    int
+	platform cl.Platform
+	name     string
+	devices  []clDevice
+}
+
+// clDevice is an OpenCL device.  Its index counts the devices of every type
+// across all platforms in order, which is how devices are referred to in the
+// config.
+type clDevice struct {
+	index      int
+	device     cl.Device
+	name       string
+	deviceType cl.CL_device_type
+}
+
+// loadOpenCL loads the OpenCL library, which is the one set with
+// --opencllibrary if any.
+func loadOpenCL() error {
+	cl.Library = cfg.ClLibrary
+	return cl.Load()
+}
+
+// enumerateCLPlatforms returns every OpenCL platform with its devices.
+func enumerateCLPlatforms() ([]clPlatform, error) {
+	err := loadOpenCL()
+	if err != nil {
+		return nil, err
+	}
+
+	clPlatforms, err := cl.Platforms()
+	if err != nil {
+		return nil, fmt.Errorf("Could not get CL platforms: %v", err)
+	}
+
+	platforms := make([]clPlatform, 0, len(clPlatforms))
+	deviceIndex := 0
+	for i, platform := range clPlatforms {
+		p := clPlatform{
+			index:    i,
+			platform: platform,
+			name: getPlatformInfo(platform, cl.CL_PLATFORM_NAME,
+				"CL_PLATFORM_NAME"),
+		}
+
+		devices, err := platform.Devices(cl.CL_DEVICE_TYPE_ALL)
+		if err != nil {
+			return nil, fmt.Errorf("Could not get CL devices for "+
+				"platform %s: %v", p.name, err)
+		}
+		for _, device := range devices {
+			d := clDevice{
+				index:  deviceIndex,
+				device: device,
+				name: getDeviceInfo(device, cl.CL_DEVICE_NAME,
+					"CL_DEVICE_NAME"),
+			}
+			d.deviceType, _ = device.Type()
+			p.devices = append(p.devices, d)
+			deviceIndex++
+		}
+
+		platforms = append(platforms, p)
+	}
+	return platforms, nil
+}
+
+// clDeviceSettings are the settings an OpenCL device is mined with.
+type clDeviceSettings struct {
+	intensity int
+	autoTune  time.Duration // target kernel run time, zero if not tuning
+	worksize  int
+	kernels   map[string]string // kernels by algorithm name
+	defines   []string
+}
+
+// clDeviceConfig is a --deviceconfig entry overriding the settings of the
+// devices matching its selector.  Only the settings it sets are overridden,
+// while defines are added to those of earlier entries.  Setting the intensity
+// turns off auto-tuning unless the entry sets autotune as well.
+type clDeviceConfig struct {
+	devices   clSelector
+	intensity int
+	autoTune  time.Duration
+	worksize  int
+	kernels   map[string]string
+	defines   []string
+}
+
+// clDeviceConfigKernels maps the --deviceconfig kernel keys to the algorithm
+// each one sets the kernel file of.
+var clDeviceConfigKernels = map[string]PowAlgorithm{
+	"kernel":       blake256Pow,
+	"blake3kernel": blake3Pow,
+}
+
+// parseCLDeviceConfig parses a --deviceconfig entry, which is a device
+// selector followed by space separated key=value settings, for example
+// "0,*tahiti* intensity=24 worksize=128 define=FOO=1" or "1 autotune=1s".
+func parseCLDeviceConfig(s string) (*clDeviceConfig, error) {
+	fields := strings.Fields(s)
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("Device config %q needs a device "+
+			"selector and at least one setting", s)
+	}
+
+	dc := &clDeviceConfig{
+		devices: parseCLSelector(fields[0]),
+		kernels: make(map[string]string),
+	}
+	for _, field := range fields[1:] {
+		parts := strings.SplitN(field, "=", 2)
+		if len(parts) != 2 || parts[1] == "" {
+			return nil, fmt.Errorf("Device config setting %q is "+
+				"not of the form key=value", field)
+		}
+		key, value := parts[0], parts[1]
+
+		switch key {
+		case "intensity":
+			i, err := strconv.Atoi(value)
+			if err != nil || i < minIntensity || i > maxIntensity {
+				return nil, fmt.Errorf("Device config intensity "+
+					"%v not within range %v to %v", value,
+					minIntensity, maxIntensity)
+			}
+			dc.intensity = i
+
+		case "autotune":
+			d, err := time.ParseDuration(value)
+			if err != nil || d <= 0 {
+				return nil, fmt.Errorf("Device config auto-tune "+
+					"target %v is not a positive duration", value)
+			}
+			dc.autoTune = d
+
+		case "worksize":
+			i, err := strconv.Atoi(value)
+			if err != nil || i <= 0 || i&(i-1) != 0 ||
+				i > 1<<minIntensity {
+				return nil, fmt.Errorf("Device config work size "+
+					"%v is not a power of two up to %v", value,
+					1<<minIntensity)
+			}
+			dc.worksize = i
+
+		case "define":
+			dc.defines = append(dc.defines, value)
+
+		default:
+			algo, ok := clDeviceConfigKernels[key]
+			if !ok {
+				return nil, fmt.Errorf("Unknown device config "+
+					"setting %q", key)
+			}
+			dc.kernels[algo.Name()] = value
+		}
+	}
+	return dc, nil
+}
+
+// clSettingsFor returns the settings of the device with the given index and
+// name, which are the global settings overridden by every matching
+// --deviceconfig entry in order.
+func clSettingsFor(index int, name string) clDeviceSettings {
+	settings := clDeviceSettings{
+		intensity: cfg.Intensity,
+		autoTune:  cfg.AutoTune,
+		worksize:  localWorksize,
+		kernels:   make(map[string]string),
+	}
+	for _, dc := range cfg.clDeviceConfigs {
+		if !dc.devices.matches(index, name) {
+			continue
+		}
+		if dc.intensity != 0 {
+			settings.intensity = dc.intensity
+			settings.autoTune = 0
+		}
+		if dc.autoTune != 0 {
+			settings.autoTune = dc.autoTune
+		}
+		if dc.worksize != 0 {
+			settings.worksize = dc.worksize
+		}
+		for algo, file := range dc.kernels {
+			settings.kernels[algo] = file
+		}
+		settings.defines = append(settings.defines, dc.defines...)
+	}
+	return settings
+}
+
+// clBackend is the Backend for OpenCL capable devices.
+type clBackend struct {
+	platforms      clSelector
+	deviceType     cl.CL_device_type
+	devices        clSelector
+	excludeDevices clSelector
+}
+
+func newCLBackend() (Backend, error) {
+	err := loadOpenCL()
+	if err != nil {
+		return nil, unavailableError{err}
+	}
+
+	deviceType, ok := clDeviceTypes[strings.ToLower(cfg.ClDeviceType)]
+	if !ok {
+		return nil, fmt.Errorf("Unknown OpenCL device type %q -- "+
+			"supported types %v", cfg.ClDeviceType, clDeviceTypeNames())
+	}
+	return &clBackend{
+		platforms:      parseCLSelector(cfg.ClPlatforms),
+		deviceType:     deviceType,
+		devices:        parseCLSelector(cfg.ClDevices),
+		excludeDevices: parseCLSelector(cfg.ClExcludeDevices),
+	}, nil
+}
+
+// Name returns the name of the OpenCL backend.
+func (b *clBackend) Name() string {
+	return "opencl"
+}
+
+// Workers returns a worker for each selected OpenCL device.
+func (b *clBackend) Workers() ([]Worker, error) {
+	platforms, err := enumerateCLPlatforms()
+	if err != nil {
+		return nil, err
+	}
+	if len(platforms) == 0 {
+		return nil, fmt.Errorf("No OpenCL platforms found")
+	}
+
+	var workers []Worker
+	platformMatched := false
+	for _, p := range platforms {
+		if len(b.platforms) != 0 && !b.platforms.matches(p.index, p.name) {
+			minrLog.Debugf("Skipping OpenCL platform #%d: %s", p.index,
+				p.name)
+			continue
+		}
+		platformMatched = true
+		minrLog.Infof("Using OpenCL platform #%d: %s", p.index, p.name)
+
+		for _, d := range p.devices {
+			if d.deviceType&b.deviceType == 0 ||
+				(len(b.devices) != 0 && !b.devices.matches(d.index, d.name)) ||
+				b.excludeDevices.matches(d.index, d.name) {
+				minrLog.Debugf("Skipping OpenCL device #%d: %s",
+					d.index, d.name)
+				continue
+			}
+
+			w, err := newCLWorker(libCLDevice{p.platform, d.device},
+				clSettingsFor(d.index, d.name))
+			if err != nil {
+				for _, w := range workers {
+					w.Release()
+				}
+				return nil, err
+			}
+			workers = append(workers, w)
+		}
+	}
+	if !platformMatched {
+		return nil, fmt.Errorf("No OpenCL platform matches %q",
+			cfg.ClPlatforms)
+	}
+
+	return workers, nil
+}
+
+// clKernel is a search kernel built for one proof-of-work algorithm.
+type clKernel struct {
+	program        clProgramAPI
+	kernel         clKernelAPI
+	globalWorksize uint32
+	tuned          bool
+}
+
+// Close releases the kernel and its program.
+func (k *clKernel) Close() {
+	k.kernel.Close()
+	k.program.Close()
+}
+
+// clSearch is a search queued on the device.
+type clSearch struct {
+	buffer int
+	count  uint32
+	read   clEventAPI // completes once the output buffer was read back
+}
+
+// clWorker runs the search kernel on a single OpenCL device.
+type clWorker struct {
+	device         clDeviceAPI
+	deviceName     string
+	context        clContextAPI
+	queue          clQueueAPI
+	outputBuffers  [numOutputBuffers]clBufferAPI
+	kernels        map[string]*clKernel
+	kernel         clKernelAPI
+	settings       clDeviceSettings
+	globalWorksize uint32
+
+	// outputData is the host memory the output buffers are read into and
+	// zero the word clearing their found counts.  They are allocated
+	// outside of the Go heap since the transfers do not block.
+	outputData [numOutputBuffers]clHostMemAPI
+	zero       clHostMemAPI
+
+	// nextBuffer is the output buffer of the next search.  The queued
+	// searches use the buffers before it, oldest first.
+	nextBuffer int
+	queued     []clSearch
+}
+
+// newCLWorker returns a worker for the device, which is the OpenCL library's
+// or a fake one.
+func newCLWorker(device clDeviceAPI, settings clDeviceSettings) (*clWorker, error) {
+	w := &clWorker{
+		device:         device,
+		deviceName:     device.Name(),
+		settings:       settings,
+		globalWorksize: 1 << uint(settings.intensity),
+		kernels:        make(map[string]*clKernel),
+	}
+	minrLog.Debugf("%s: intensity %d, auto-tune %v, work size %d, "+
+		"defines %v", w.deviceName, settings.intensity,
+		settings.autoTune, settings.worksize, settings.defines)
+
+	err := w.init()
+	if err != nil {
+		w.Release()
+		return nil, err
+	}
+	return w, nil
+}
+
+// init creates the OpenCL objects of the worker.
+func (w *clWorker) init() error {
+	var err error
+
+	// Create the CL context
+	w.context, err = w.device.CreateContext()
+	if err != nil {
+		return err
+	}
+
+	// Create the command queue
+	w.queue, err = w.context.CreateQueue()
+	if err != nil {
+		return err
+	}
+
+	// Create the output buffers
+	for i := range w.outputBuffers {
+		w.outputBuffers[i], err = w.context.CreateBuffer(
+			4 * outputBufferSize)
+		if err != nil {
+			return err
+		}
+		w.outputData[i], err = w.context.CreateHostMem(outputBufferSize)
+		if err != nil {
+			return err
+		}
+	}
+	w.zero, err = w.context.CreateHostMem(1)
+	if err != nil {
+		return err
+	}
+
+	// Build the kernels up front so build errors show up at startup.
+	for _, algo := range configuredPowAlgorithms() {
+		k, err := w.buildKernel(algo)
+		if err != nil {
+			return err
+		}
+		w.kernels[algo.Name()] = k
+	}
+
+	return nil
+}
+
+// buildKernel loads, builds and creates the search kernel for the algorithm.
+// The program is loaded from the binary cache when there is a binary for the
+// device, kernel source and compiler options, and cached after building it
+// from source otherwise.
+func (w *clWorker) buildKernel(algo PowAlgorithm) (*clKernel, error) {
+	k := &clKernel{
+		globalWorksize: 1 << uint(w.settings.intensity),
+	}
+
+	// Load kernel source
+	kernel, ok := w.settings.kernels[algo.Name()]
+	if !ok {
+		kernel = algo.KernelSource()
+	}
+	source, err := loadKernelSource(kernel)
+	if err != nil {
+		return nil, fmt.Errorf("Could not load kernel source: %v", err)
+	}
+
+	compilerOptions := ""
+	compilerOptions += fmt.Sprintf(" -D WORKSIZE=%d", w.settings.worksize)
+	for _, define := range w.settings.defines {
+		compilerOptions += " -D " + define
+	}
+
+	key := w.programCacheKey(source, compilerOptions)
+	program, ok := w.loadCachedProgram(key, compilerOptions)
+	if !ok {
+		program, err = w.buildProgram(source, compilerOptions)
+		if err != nil {
+			return nil, err
+		}
+		w.cacheProgram(key, program)
+	}
+	k.program = program
+
+	// Create the kernel
+	k.kernel, err = k.program.CreateKernel("search")
+	if err != nil {
+		k.program.Close()
+		return nil, err
+	}
+
+	return k, nil
+}
+
+// buildProgram creates a program from the source and builds it for the
+// device with the compiler options.
+func (w *clWorker) buildProgram(source []byte, compilerOptions string) (clProgramAPI, error) {
+	// Create the program
+	program, err := w.context.CreateProgramWithSource(source)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build the program for the device
+	err = program.Build(compilerOptions)
+	if err != nil {
+		// Something went wrong! Print what it is.
+		buildLog, logErr := program.BuildLog()
+		if logErr != nil {
+			minrLog.Errorf("Could not obtain compilation error log: %v", logErr)
+		}
+		minrLog.Errorf("%s\n", buildLog)
+
+		program.Close()
+		return nil, err
+	}
+
+	return program, nil
+}
+
+// Name returns the OpenCL device name.
+func (w *clWorker) Name() string {
+	return w.deviceName
+}
+
+// BatchSize returns the global work size of the current kernel, which is
+// derived from the configured or the tuned intensity.
+func (w *clWorker) BatchSize() uint32 {
+	return w.globalWorksize
+}
+
+// SetWork selects the kernel of the algorithm, building it the first time it
+// is needed, and sets its arguments for the passed work.  When auto-tuning,
+// the intensity of a new kernel is tuned using the work before it is used.
+func (w *clWorker) SetWork(algo PowAlgorithm, midstate *[8]uint32, lastBlock *[16]uint32) error {
+	k, ok := w.kernels[algo.Name()]
+	if !ok {
+		var err error
+		k, err = w.buildKernel(algo)
+		if err != nil {
+			return err
+		}
+		w.kernels[algo.Name()] = k
+	}
+	w.kernel = k.kernel
+
+	// arg 0, the output buffer, is set for every search.
+
+	// args 1..8: midstate
+	for i := 0; i < 8; i++ {
+		err := w.kernel.SetArgUint32(i+1, midstate[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	// args 9..20: lastBlock except nonce
+	i2 := 0
+	for i := 0; i < 12; i++ {
+		if i2 == nonce0Word {
+			i2++
+		}
+		err := w.kernel.SetArgUint32(i+9, lastBlock[i2])
+		if err != nil {
+			return err
+		}
+		i2++
+	}
+
+	if w.settings.autoTune > 0 && !k.tuned {
+		err := w.autoTune(algo, k)
+		if err != nil {
+			return err
+		}
+		k.tuned = true
+	}
+	w.globalWorksize = k.globalWorksize
+
+	return nil
+}
+
+// autoTune sets the global work size of the kernel to the one whose runs take
+// closest to the auto-tune target, or to the one saved for the device by an
+// earlier tuning.  The work size only ever doubles, so the intensity is raised
+// one at a time until a run takes at least as long as the target.  The runs
+// search the current work, but whatever they find is found again by the first
+// real search.
+func (w *clWorker) autoTune(algo PowAlgorithm, k *clKernel) error {
+	target := w.settings.autoTune
+	key := autoTuneKey(w.deviceName, algo, target)
+	if intensity, ok := loadTunedIntensity(key); ok {
+		minrLog.Infof("%s: using saved %s intensity %d for a kernel "+
+			"run time of %v", w.deviceName, algo.Name(), intensity,
+			target)
+		k.globalWorksize = 1 << uint(intensity)
+		return nil
+	}
+
+	// Warm up so the first timed run does not include any one time setup
+	// done by the driver.
+	_, _, err := w.Search(0, 1<<uint(minIntensity))
+	if err != nil {
+		return err
+	}
+
+	intensity := maxIntensity
+	var elapsed, prevElapsed time.Duration
+	for i := minIntensity; i <= maxIntensity; i++ {
+		started := time.Now()
+		_, _, err := w.Search(0, 1<<uint(i))
+		if err != nil {
+			return err
+		}
+		prevElapsed, elapsed = elapsed, time.Since(started)
+		if elapsed < target {
+			continue
+		}
+
+		// Go back one if that run was closer to the target, as a
+		// ratio since the run time doubles with every step.
+		intensity = i
+		if i > minIntensity && float64(target)/float64(prevElapsed) <
+			float64(elapsed)/float64(target) {
+			intensity = i - 1
+			elapsed = prevElapsed
+		}
+		break
+	}
+
+	minrLog.Infof("%s: tuned %s intensity to %d, taking %v per kernel run "+
+		"for a target of %v", w.deviceName, algo.Name(), intensity,
+		elapsed, target)
+	k.globalWorksize = 1 << uint(intensity)
+
+	if cfg.AutoTuneSave {
+		err := saveTunedIntensity(key, intensity)
+		if err != nil {
+			minrLog.Warnf("Unable to save tuned intensity: %v", err)
+		}
+	}
+	return nil
+}
+
+// Search runs the kernel over count nonces starting at start and reads back
+// the nonces it found.  It uses the next free output buffer, so it may be
+// called while there is a queued search.
+func (w *clWorker) Search(start, count uint32) ([]uint32, uint64, error) {
+	search, err := w.enqueue(w.nextBuffer, start, count)
+	if err != nil {
+		return nil, 0, err
+	}
+	return w.results(search)
+}
+
+// QueueSize returns the number of searches that can be queued at once.
+func (w *clWorker) QueueSize() int {
+	return numOutputBuffers
+}
+
+// Enqueue queues a search of count nonces starting at start with the current
+// work and returns without waiting for it.
+func (w *clWorker) Enqueue(start, count uint32) error {
+	if len(w.queued) == numOutputBuffers {
+		return fmt.Errorf("%s: all %d output buffers are in use",
+			w.deviceName, numOutputBuffers)
+	}
+	search, err := w.enqueue(w.nextBuffer, start, count)
+	if err != nil {
+		w.dropQueued()
+		return err
+	}
+	w.queued = append(w.queued, search)
+	w.nextBuffer = (w.nextBuffer + 1) % numOutputBuffers
+	return nil
+}
+
+// Wait waits for the oldest queued search and returns the nonces it found.
+func (w *clWorker) Wait() ([]uint32, uint64, error) {
+	if len(w.queued) == 0 {
+		return nil, 0, fmt.Errorf("%s: no search is queued", w.deviceName)
+	}
+	search := w.queued[0]
+	w.queued = w.queued[1:]
+	candidates, hashes, err := w.results(search)
+	if err != nil {
+		w.dropQueued()
+		return nil, 0, err
+	}
+	return candidates, hashes, nil
+}
+
+// dropQueued waits for the queued searches to finish and forgets them, so
+// that the worker starts over with an empty queue after an error.
+func (w *clWorker) dropQueued() {
+	if w.queue != nil {
+		w.queue.Finish()
+	}
+	for _, search := range w.queued {
+		search.read.Close()
+	}
+	w.queued = nil
+}
+
+// enqueue queues the commands clearing the output buffer, running the kernel
+// with it and reading it back, all without blocking.
+func (w *clWorker) enqueue(buffer int, start, count uint32) (clSearch, error) {
+	search := clSearch{buffer: buffer, count: count}
+
+	// Clear the found count from the buffer
+	outputBuffer := w.outputBuffers[buffer]
+	event, err := w.queue.EnqueueWriteBuffer(outputBuffer, false, 0, w.zero)
+	if err != nil {
+		return search, err
+	}
+	event.Close()
+
+	// arg 0: pointer to the buffer
+	err = w.kernel.SetArgBuffer(0, outputBuffer)
+	if err != nil {
+		return search, err
+	}
+
+	// Execute the kernel
+	event, err = w.queue.EnqueueKernel(w.kernel, int(start), int(count),
+		w.settings.worksize)
+	if err != nil {
+		return search, err
+	}
+	event.Close()
+
+	// Read the output buffer once the kernel is done.  The queue runs the
+	// commands in order, so nothing needs to wait for the kernel event.
+	search.read, err = w.queue.EnqueueReadBuffer(outputBuffer, false, 0,
+		w.outputData[buffer])
+	if err != nil {
+		return search, err
+	}
+
+	// Make sure the device starts on the commands right away.
+	err = w.queue.Flush()
+	if err != nil {
+		search.read.Close()
+		return search, err
+	}
+
+	return search, nil
+}
+
+// results waits for the output buffer of the search to be read back and
+// returns the nonces in it.
+func (w *clWorker) results(search clSearch) ([]uint32, uint64, error) {
+	err := search.read.Wait()
+	search.read.Close()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	outputData := w.outputData[search.buffer].Words()
+	numFound := outputData[0]
+	if numFound >= outputBufferSize {
+		numFound = outputBufferSize - 1
+	}
+	candidates := make([]uint32, numFound)
+	copy(candidates, outputData[1:])
+
+	return candidates, uint64(search.count), nil
+}
+
+// Release frees the OpenCL objects owned by the worker.  It also frees the
+// objects of a worker whose creation failed part way.
+func (w *clWorker) Release() {
+	// Let the queued searches finish since they write to the buffers.
+	w.dropQueued()
+
+	for _, k := range w.kernels {
+		k.Close()
+	}
+	if w.queue != nil {
+		w.queue.Close()
+	}
+	for _, buffer := range w.outputBuffers {
+		if buffer != nil {
+			buffer.Close()
+		}
+	}
+	for _, data := range w.outputData {
+		if data != nil {
+			data.Close()
+		}
+	}
+	if w.zero != nil {
+		w.zero.Close()
+	}
+	if w.context != nil {
+		w.context.Close()
+	}
+}
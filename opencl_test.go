@@ -0,0 +1,199 @@
+// Copyright (c) 2026 The Decred developers
+
+package main
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/decred/gominer/cl"
+)
+
+// clTestSettings are the settings of the workers of the fake device, whose
+// batches are 1024 nonces.
+var clTestSettings = clDeviceSettings{intensity: 10, worksize: 64}
+
+// useCLTestConfig configures the embedded kernels of the algorithm without
+// the kernel cache.
+func useCLTestConfig(algo PowAlgorithm) {
+	cfg = &config{
+		Algo:           algo.Name(),
+		ClKernel:       defaultClKernel,
+		ClKernelBlake3: defaultClKernelBlake3,
+		NoKernelCache:  true,
+	}
+}
+
+// wantCandidates returns the nonces from start to start+count whose final
+// hash word has the bits of mask zero.
+func wantCandidates(algo PowAlgorithm, midstate *[8]uint32, lastBlock *[16]uint32, start, count, mask uint32) []uint32 {
+	var nonces []uint32
+	for nonce := start; nonce < start+count; nonce++ {
+		h := algo.FinalBlock(midstate, lastBlock, nonce)
+		if h[7]&mask == 0 {
+			nonces = append(nonces, nonce)
+		}
+	}
+	return nonces
+}
+
+// TestCLWorker runs searches with the OpenCL worker on the fake device and
+// checks the candidates it reports, the cap on the number of candidates of a
+// search, the recovery from a temporary error and that it releases every
+// OpenCL object.
+func TestCLWorker(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, algo := range powAlgorithms {
+		useCLTestConfig(algo)
+		dev := newFakeCLDevice("Fake device")
+		dev.candidateBits = 8
+		w, err := newCLWorker(dev, clTestSettings)
+		if err != nil {
+			t.Fatalf("%s: %v", algo.Name(), err)
+		}
+
+		var data [192]byte
+		r.Read(data[:headerBits/8])
+		midstate := algo.Midstate(data[:])
+		lastBlock := algo.LastBlock(data[:])
+		err = w.SetWork(algo, &midstate, &lastBlock)
+		if err != nil {
+			t.Fatalf("%s: %v", algo.Name(), err)
+		}
+		batch := w.BatchSize()
+		check := func(what string, start uint32, got []uint32, hashes uint64, err error, want []uint32) {
+			t.Helper()
+			if err != nil {
+				t.Fatalf("%s: %s: %v", algo.Name(), what, err)
+			}
+			same := len(got) == 0 && len(want) == 0 ||
+				reflect.DeepEqual(got, want)
+			if !same || hashes != uint64(batch) {
+				t.Fatalf("%s: %s from %d found %v in %d hashes, "+
+					"want %v in %d", algo.Name(), what, start, got,
+					hashes, want, batch)
+			}
+		}
+		mask := dev.candidateMask()
+
+		// Plain searches and queued ones give the same candidates.
+		for start := uint32(0); start < 4*batch; start += batch {
+			got, hashes, err := w.Search(start, batch)
+			check("search", start, got, hashes, err,
+				wantCandidates(algo, &midstate, &lastBlock, start,
+					batch, mask))
+		}
+		for start := uint32(0); start < 4*batch; start += 2 * batch {
+			for i := uint32(0); i < 2; i++ {
+				err := w.Enqueue(start+i*batch, batch)
+				if err != nil {
+					t.Fatalf("%s: %v", algo.Name(), err)
+				}
+			}
+			if err := w.Enqueue(start, batch); err == nil {
+				t.Fatalf("%s: queued more searches than there "+
+					"are output buffers", algo.Name())
+			}
+			for i := uint32(0); i < 2; i++ {
+				got, hashes, err := w.Wait()
+				check("queued search", start+i*batch, got, hashes,
+					err, wantCandidates(algo, &midstate,
+						&lastBlock, start+i*batch, batch, mask))
+			}
+		}
+
+		// A search reports as many candidates as fit its output
+		// buffer, the first ones it found.
+		dev.candidateBits = 1
+		got, hashes, err := w.Search(0, batch)
+		want := wantCandidates(algo, &midstate, &lastBlock, 0, batch,
+			dev.candidateMask())
+		if len(want) < outputBufferSize {
+			t.Fatalf("%s: only %d candidates", algo.Name(), len(want))
+		}
+		check("full search", 0, got, hashes, err,
+			want[:outputBufferSize-1])
+		dev.candidateBits = 8
+
+		// A temporary error drops the queued searches, and searching
+		// works again once the error is gone.
+		err = w.Enqueue(0, batch)
+		if err != nil {
+			t.Fatalf("%s: %v", algo.Name(), err)
+		}
+		dev.kernelErr = fakeCLError(cl.CL_OUT_OF_RESOURCES,
+			"CLEnqueueNDRangeKernel")
+		err = w.Enqueue(batch, batch)
+		if !isTemporary(err) {
+			t.Fatalf("%s: out of resources error %v is not "+
+				"temporary", algo.Name(), err)
+		}
+		if _, _, err := w.Wait(); err == nil {
+			t.Fatalf("%s: searches are still queued after an error",
+				algo.Name())
+		}
+		dev.kernelErr = nil
+		got, hashes, err = w.Search(batch, batch)
+		check("search after an error", batch, got, hashes, err,
+			wantCandidates(algo, &midstate, &lastBlock, batch, batch,
+				mask))
+
+		w.Release()
+		if dev.open != 0 {
+			t.Fatalf("%s: %d objects not released", algo.Name(),
+				dev.open)
+		}
+	}
+}
+
+// TestCLDeviceRetry checks that a device restarts its OpenCL worker after
+// temporary errors and then reports the solutions of the worker.
+func TestCLDeviceRetry(t *testing.T) {
+	defer func(delay time.Duration) {
+		deviceRetryDelay = delay
+	}(deviceRetryDelay)
+	deviceRetryDelay = time.Millisecond
+
+	for _, algo := range powAlgorithms {
+		useCLTestConfig(algo)
+		dev := newFakeCLDevice("Fake device")
+		dev.candidateBits = 8
+		dev.kernelErr = fakeCLError(cl.CL_OUT_OF_RESOURCES,
+			"CLEnqueueNDRangeKernel")
+		dev.failures = maxDeviceRetries
+		w, err := newCLWorker(dev, clTestSettings)
+		if err != nil {
+			t.Fatalf("%s: %v", algo.Name(), err)
+		}
+
+		solved := make(chan *Work, 10)
+		d := NewDevice(0, w, solved)
+		done := make(chan struct{})
+		go func() {
+			d.Run()
+			d.Release()
+			close(done)
+		}()
+		work := &Work{Algorithm: algo}
+		for i := range work.Target {
+			work.Target[i] = 0xff
+		}
+		d.SetWork(work)
+
+		select {
+		case <-solved:
+		case <-done:
+			t.Fatalf("%s: device gave up", algo.Name())
+		case <-time.After(10 * time.Second):
+			t.Fatalf("%s: no solution", algo.Name())
+		}
+		d.Stop()
+		<-done
+		if dev.open != 0 {
+			t.Fatalf("%s: %d objects not released", algo.Name(),
+				dev.open)
+		}
+	}
+}
@@ -0,0 +1,598 @@
+// Copyright (c) 2026 The Decred developers
+
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// poolStrategyFailover mines on the first pool that works.
+	poolStrategyFailover = "failover"
+
+	// poolStrategyBalance splits the devices between all pools that work
+	// by their weights.
+	poolStrategyBalance = "balance"
+
+	// poolBalanceSlice is how long devices mine for the pools they are
+	// given when balancing before they are given pools again.
+	poolBalanceSlice = time.Minute
+)
+
+// poolConfig is a pool to mine on with the credentials to log in with.
+type poolConfig struct {
+	url    string
+	user   string
+	pass   string
+	weight int
+
+	// cert is the file with the CA or certificate the TLS certificate of
+	// the pool is verified against instead of the system roots, when
+	// set.  fingerprint is the SHA-256 fingerprint of the certificate of
+	// the pool, when set, in which case the certificate is trusted when
+	// it has the fingerprint, whoever issued it.  skipVerify turns off
+	// the verification of the certificate when there is no fingerprint.
+	cert        string
+	fingerprint []byte
+	skipVerify  bool
+}
+
+// tlsConfig returns the TLS settings for connecting to the pool.  When the
+// pool has a fingerprint, checking it takes the place of the verification of
+// the certificate chain, which is turned off for the pool.
+func (pc *poolConfig) tlsConfig() (*tls.Config, error) {
+	config := &tls.Config{InsecureSkipVerify: pc.skipVerify}
+	if pc.cert != "" {
+		pem, err := ioutil.ReadFile(pc.cert)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("No certificates found in %v",
+				pc.cert)
+		}
+		config.RootCAs = pool
+	}
+	if pc.fingerprint != nil {
+		want := pc.fingerprint
+		config.InsecureSkipVerify = true
+		config.VerifyConnection = func(cs tls.ConnectionState) error {
+			if len(cs.PeerCertificates) == 0 {
+				return fmt.Errorf("Pool sent no certificate")
+			}
+			got := sha256.Sum256(cs.PeerCertificates[0].Raw)
+			if !bytes.Equal(got[:], want) {
+				return fmt.Errorf("Pool certificate fingerprint "+
+					"%x does not match %x", got, want)
+			}
+			return nil
+		}
+	}
+	return config, nil
+}
+
+// parseFingerprint parses a SHA-256 certificate fingerprint in hex, with or
+// without colons between the bytes.
+func parseFingerprint(s string) ([]byte, error) {
+	b, err := hex.DecodeString(strings.Replace(s, ":", "", -1))
+	if err != nil || len(b) != sha256.Size {
+		return nil, fmt.Errorf("Certificate fingerprint %q is not a "+
+			"SHA-256 hash in hex", s)
+	}
+	return b, nil
+}
+
+// parsePoolConfig parses a --backuppool entry, which is the URL of the pool
+// followed by space separated settings of the form key=value.
+func parsePoolConfig(s string) (*poolConfig, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("Pool config %q needs a pool URL", s)
+	}
+
+	pc := &poolConfig{url: fields[0], weight: 1}
+	for _, field := range fields[1:] {
+		parts := strings.SplitN(field, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("Pool config setting %q is not "+
+				"of the form key=value", field)
+		}
+		key, value := parts[0], parts[1]
+
+		switch key {
+		case "user":
+			pc.user = value
+		case "pass":
+			pc.pass = value
+		case "cert":
+			pc.cert = cleanAndExpandPath(value)
+		case "fingerprint":
+			fp, err := parseFingerprint(value)
+			if err != nil {
+				return nil, err
+			}
+			pc.fingerprint = fp
+		case "weight":
+			w, err := strconv.Atoi(value)
+			if err != nil || w < 1 {
+				return nil, fmt.Errorf("Pool config weight %v is "+
+					"not a positive integer", value)
+			}
+			pc.weight = w
+		default:
+			return nil, fmt.Errorf("Unknown pool config setting %q",
+				key)
+		}
+	}
+	return pc, nil
+}
+
+// poolManager decides which pool each device mines for, using one of two
+// strategies.
+//
+// With poolStrategyFailover, all devices mine on the first of the configured
+// pools that works.  The manager fails over to the next pool when the current
+// one cannot be connected to, rejects the credentials, loses the connection or
+// stops sending work, and fails back when a pool before it works again.  There
+// are sessions for the pools from the first up to the current one.  The
+// sessions of the pools before the current one keep reconnecting in the
+// background, which is how they are probed, and the sessions of the pools
+// after it are closed.
+//
+// With poolStrategyBalance, there are sessions for all pools, and every
+// poolBalanceSlice the devices are split between the pools that work by their
+// weights with smooth weighted round-robin.  The order the devices are handed
+// out in rotates, so that devices of different speeds take turns on every
+// pool and a split the devices cannot be divided into evenly comes out right
+// over time.
+type poolManager struct {
+	pools       []*poolConfig
+	workTimeout time.Duration
+	strategy    string
+
+	// The fields below are only changed by the run goroutine, which reads
+	// them without holding mtx.
+	mtx        sync.Mutex
+	sessions   []*Stratum
+	current    int
+	switches   int
+	switchedAt time.Time
+	lastReason string
+
+	// assigned is the pool of every device when balancing, or -1 when
+	// no pool works.
+	assigned []int
+
+	// The fields below are the balancing state, which is only used by the
+	// run goroutine: the pools that worked at the last split, the smooth
+	// weighted round-robin credit of every pool, the number of splits and
+	// the time of the last one.
+	balanced []bool
+	credit   []int
+	splits   int
+	splitAt  time.Time
+
+	// connecting is set while a pool to fail over to is being connected
+	// to in the background, whose result comes in on connected.  It is
+	// only used by the run goroutine.
+	connecting bool
+	connected  chan poolConnectResult
+
+	quit      chan struct{}
+	closeOnce sync.Once
+}
+
+// poolConnectResult is the session of a pool that was connected to in the
+// background, which keeps probing the pool when err is set.  The session is
+// nil when it could not be set up at all.
+type poolConnectResult struct {
+	pool    int
+	session *Stratum
+	err     error
+}
+
+// newPoolManager returns a manager for the pools, in order of priority, that
+// hands them out to the given number of devices with the strategy.
+func newPoolManager(pools []*poolConfig, workTimeout time.Duration, strategy string, devices int) *poolManager {
+	assigned := make([]int, devices)
+	for i := range assigned {
+		assigned[i] = -1
+	}
+	return &poolManager{
+		pools:       pools,
+		workTimeout: workTimeout,
+		strategy:    strategy,
+		sessions:    make([]*Stratum, len(pools)),
+		assigned:    assigned,
+		balanced:    make([]bool, len(pools)),
+		credit:      make([]int, len(pools)),
+		connected:   make(chan poolConnectResult),
+		quit:        make(chan struct{}),
+	}
+}
+
+// start connects to the first pool that works, or to all pools when
+// balancing, and starts managing the pools in the background.  It fails when
+// none of them work.
+func (pm *poolManager) start() error {
+	if pm.strategy == poolStrategyBalance {
+		return pm.startBalance()
+	}
+
+	var errs []string
+	for i := range pm.pools {
+		err := pm.connect(i)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		pm.mtx.Lock()
+		pm.current = i
+		pm.switchedAt = time.Now()
+		pm.mtx.Unlock()
+		go pm.run()
+		return nil
+	}
+	pm.Close()
+	return fmt.Errorf("No pool could be used: %s", strings.Join(errs, "; "))
+}
+
+// connect connects to pool i.  When it fails, the session of the pool is kept
+// trying in the background so that the pool is probed.
+func (pm *poolManager) connect(i int) error {
+	s, err := pm.dial(i)
+	if s != nil {
+		pm.setSession(i, s)
+	}
+	return err
+}
+
+// connectBackground connects to pool i like connect, but in the background,
+// since connecting can take as long as the timeouts of the dial and of the
+// subscribe and authorize requests add up to.  The result comes in on
+// connected.
+func (pm *poolManager) connectBackground(i int) {
+	pm.connecting = true
+	go func() {
+		s, err := pm.dial(i)
+		select {
+		case pm.connected <- poolConnectResult{i, s, err}:
+		case <-pm.quit:
+			if s != nil {
+				s.Close()
+			}
+		}
+	}()
+}
+
+// dial returns a session of pool i, which is connected to the pool, or which
+// keeps trying to connect to it in the background when it fails.
+func (pm *poolManager) dial(i int) (*Stratum, error) {
+	pc := pm.pools[i]
+	tlsConfig, err := pc.tlsConfig()
+	if err != nil {
+		return nil, fmt.Errorf("Pool %v: %v", pc.url, err)
+	}
+	s, err := newStratum(pc.url, pc.user, pc.pass, tlsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("Pool %v: %v", pc.url, err)
+	}
+	err = s.connect()
+	if err != nil {
+		s.probe()
+		poolLog.Errorf("Pool %v failed: %v", pc.url, err)
+		return s, fmt.Errorf("Pool %v: %v", pc.url, err)
+	}
+	return s, nil
+}
+
+// setSession makes s the session of pool i, unless the manager was closed.
+func (pm *poolManager) setSession(i int, s *Stratum) {
+	pm.mtx.Lock()
+	defer pm.mtx.Unlock()
+	select {
+	case <-pm.quit:
+		// Closed while connecting.
+		s.Close()
+	default:
+		pm.sessions[i] = s
+	}
+}
+
+// startBalance connects to all pools and starts balancing between them in the
+// background.  It fails when none of them work.
+func (pm *poolManager) startBalance() error {
+	var errs []string
+	for i := range pm.pools {
+		err := pm.connect(i)
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) == len(pm.pools) {
+		pm.Close()
+		return fmt.Errorf("No pool could be used: %s",
+			strings.Join(errs, "; "))
+	}
+	go pm.run()
+	return nil
+}
+
+// run checks the pools every second, and as soon as a pool connected to in the
+// background is ready, until the manager is closed.
+func (pm *poolManager) run() {
+	t := time.NewTicker(time.Second)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-pm.quit:
+			return
+		case <-t.C:
+		case res := <-pm.connected:
+			pm.connectDone(res)
+		}
+		pm.update()
+	}
+}
+
+// connectDone takes the session of a pool connected to in the background.  It
+// is dropped when the current pool works again by now, since the sessions
+// after the current one are closed.
+func (pm *poolManager) connectDone(res poolConnectResult) {
+	pm.connecting = false
+	if res.session == nil {
+		return
+	}
+	if pm.failure() == "" {
+		res.session.Close()
+		return
+	}
+	pm.setSession(res.pool, res.session)
+}
+
+// works returns whether the session of pool i is connected and has recently
+// sent work.
+func (pm *poolManager) works(i int) bool {
+	s := pm.sessions[i]
+	if s == nil || !s.Connected() {
+		return false
+	}
+	age, hasWork := s.WorkAge()
+	return hasWork && age < pm.workTimeout
+}
+
+// update fails back to the first pool before the current one that works
+// again, or fails over to the next pool that works when the current one
+// failed.
+func (pm *poolManager) update() {
+	if pm.strategy == poolStrategyBalance {
+		pm.balance()
+		return
+	}
+
+	for i := 0; i < pm.current; i++ {
+		if pm.works(i) {
+			pm.switchTo(i, "pool is back")
+			return
+		}
+	}
+
+	reason := pm.failure()
+	if reason == "" {
+		return
+	}
+
+	for i := pm.current + 1; i < len(pm.pools); i++ {
+		if pm.sessions[i] == nil {
+			// The pools are checked again once the pool is
+			// connected to, or is probed after failing.
+			if !pm.connecting {
+				pm.connectBackground(i)
+			}
+			return
+		}
+		if !pm.works(i) {
+			continue
+		}
+		pm.switchTo(i, reason)
+		return
+	}
+}
+
+// failure returns why the current pool failed, or the empty string when it
+// works.
+func (pm *poolManager) failure() string {
+	s := pm.sessions[pm.current]
+	if !s.Connected() {
+		return "connection lost"
+	}
+	if age, _ := s.WorkAge(); age >= pm.workTimeout {
+		return fmt.Sprintf("no new work for %v",
+			age.Truncate(time.Second))
+	}
+	return ""
+}
+
+// switchTo makes pool i the current pool and closes the sessions of the pools
+// after it.
+func (pm *poolManager) switchTo(i int, reason string) {
+	poolLog.Warnf("Switching from pool %v to pool %v: %s",
+		pm.pools[pm.current].url, pm.pools[i].url, reason)
+
+	pm.mtx.Lock()
+	pm.current = i
+	pm.switches++
+	pm.switchedAt = time.Now()
+	pm.lastReason = reason
+	closed := make([]*Stratum, 0, len(pm.sessions))
+	for j := i + 1; j < len(pm.sessions); j++ {
+		if pm.sessions[j] != nil {
+			closed = append(closed, pm.sessions[j])
+			pm.sessions[j] = nil
+		}
+	}
+	pm.mtx.Unlock()
+
+	for _, s := range closed {
+		s.Close()
+	}
+}
+
+// balance splits the devices between the pools that work when the slice is
+// over or the pools that work changed.
+func (pm *poolManager) balance() {
+	working := make([]bool, len(pm.pools))
+	changed := false
+	total := 0
+	for i, pc := range pm.pools {
+		working[i] = pm.works(i)
+		if working[i] != pm.balanced[i] {
+			changed = true
+		}
+		if working[i] {
+			total += pc.weight
+		}
+	}
+	if !changed && time.Since(pm.splitAt) < poolBalanceSlice {
+		return
+	}
+	if changed {
+		for i := range pm.pools {
+			if working[i] != pm.balanced[i] {
+				state := "stopped working"
+				if working[i] {
+					state = "works"
+				}
+				poolLog.Infof("Pool %v %s, splitting devices "+
+					"again", pm.pools[i].url, state)
+			}
+			pm.credit[i] = 0
+		}
+		pm.balanced = working
+	}
+
+	assigned := make([]int, len(pm.assigned))
+	n := len(assigned)
+	for k := 0; k < n; k++ {
+		d := (k + pm.splits) % n
+		assigned[d] = -1
+		if total == 0 {
+			continue
+		}
+		best := -1
+		for i, pc := range pm.pools {
+			if !working[i] {
+				continue
+			}
+			pm.credit[i] += pc.weight
+			if best == -1 || pm.credit[i] > pm.credit[best] {
+				best = i
+			}
+		}
+		pm.credit[best] -= total
+		assigned[d] = best
+	}
+	pm.splits++
+	pm.splitAt = time.Now()
+
+	pm.mtx.Lock()
+	for d, i := range assigned {
+		if i != pm.assigned[d] && i != -1 && pm.assigned[d] != -1 {
+			pm.switches++
+		}
+	}
+	pm.assigned = assigned
+	pm.mtx.Unlock()
+}
+
+// Current returns the session of the current pool.
+func (pm *poolManager) Current() *Stratum {
+	pm.mtx.Lock()
+	defer pm.mtx.Unlock()
+	return pm.sessions[pm.current]
+}
+
+// PoolFor returns the session of the pool the device mines for, or nil when
+// there is none.
+func (pm *poolManager) PoolFor(device int) *Stratum {
+	if pm.strategy != poolStrategyBalance {
+		return pm.Current()
+	}
+
+	pm.mtx.Lock()
+	defer pm.mtx.Unlock()
+	i := pm.assigned[device]
+	if i == -1 {
+		return nil
+	}
+	return pm.sessions[i]
+}
+
+// PrintStats logs the pools with their shares and the pool switches.
+func (pm *poolManager) PrintStats() {
+	pm.mtx.Lock()
+	defer pm.mtx.Unlock()
+
+	if len(pm.pools) == 1 {
+		return
+	}
+	for i, pc := range pm.pools {
+		s := pm.sessions[i]
+		if s == nil {
+			continue
+		}
+		accepted, rejected := s.Shares()
+		if pm.strategy == poolStrategyBalance {
+			devices := 0
+			for _, j := range pm.assigned {
+				if j == i {
+					devices++
+				}
+			}
+			poolLog.Infof("Pool %v: weight %d, %d devices, %d "+
+				"accepted, %d rejected", pc.url, pc.weight,
+				devices, accepted, rejected)
+		} else {
+			poolLog.Infof("Pool %v: %d accepted, %d rejected",
+				pc.url, accepted, rejected)
+		}
+	}
+	if pm.strategy == poolStrategyBalance {
+		poolLog.Infof("Pools: %d device switches", pm.switches)
+		return
+	}
+	last := ""
+	if pm.switches > 0 {
+		last = fmt.Sprintf(", last because of %s", pm.lastReason)
+	}
+	poolLog.Infof("Pool %d of %d: %v for %v, %d switches%s",
+		pm.current+1, len(pm.pools), pm.pools[pm.current].url,
+		time.Since(pm.switchedAt).Truncate(time.Second), pm.switches,
+		last)
+}
+
+// Close disconnects from all pools.
+func (pm *poolManager) Close() {
+	pm.closeOnce.Do(func() {
+		close(pm.quit)
+		pm.mtx.Lock()
+		defer pm.mtx.Unlock()
+		for _, s := range pm.sessions {
+			if s != nil {
+				s.Close()
+			}
+		}
+	})
+}
@@ -0,0 +1,350 @@
+// Copyright (c) 2026 The Decred developers
+
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestPoolBalance balances devices between two mock pools weighted 3:1.  It
+// checks that every split gives the pools their share of the devices and that
+// every device takes its turn on both pools, that the work of every pool is
+// built from the extranonce1 and target of its own session, and that a miner
+// sends each pool the shares of the devices it gave it, which the session of
+// the pool counts.
+func TestPoolBalance(t *testing.T) {
+	pools := []*mockPool{newMockPool(t, 1e-10), newMockPool(t, 2e-10)}
+	weights := []int{3, 1}
+	total := weights[0] + weights[1]
+	const devices = 4
+	var configs []*poolConfig
+	for i, pool := range pools {
+		configs = append(configs, &poolConfig{url: pool.url(),
+			user: "user", pass: "pass", weight: weights[i]})
+	}
+
+	// The manager is not started, so that the splits are made here and
+	// not by its run goroutine.
+	pm := newPoolManager(configs, time.Minute, poolStrategyBalance, devices)
+	defer pm.Close()
+	for i := range pools {
+		if err := pm.connect(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	waitFor(t, "work from both pools", func() bool {
+		return pm.works(0) && pm.works(1)
+	})
+	for i, pool := range pools {
+		s := pm.sessions[i]
+		work, err := GetPoolWork(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		en1 := hex.EncodeToString(work.Data[144:148])
+		if want := pool.extraNonce1(0); en1 != want {
+			t.Errorf("Work of pool %d has extranonce1 %s, want %s",
+				i, en1, want)
+		}
+		target := hex.EncodeToString(reverse(work.Target[:]))
+		if work.Pool != s || target != s.diffToTarget(pool.diff) {
+			t.Errorf("Work of pool %d is from session %p with "+
+				"target %s, want %p with the target of "+
+				"difficulty %v", i, work.Pool, target, s,
+				pool.diff)
+		}
+	}
+
+	// Over as many slices as the weights add up to, every device mines
+	// for every pool for as many slices as the weight of the pool.
+	turns := make([][]int, devices)
+	for d := range turns {
+		turns[d] = make([]int, len(pools))
+	}
+	for slice := 0; slice < total; slice++ {
+		pm.splitAt = time.Time{}
+		pm.balance()
+		counts := make([]int, len(pools))
+		for d, i := range pm.assigned {
+			if i == -1 {
+				t.Fatalf("Slice %d: device %d has no pool", slice, d)
+			}
+			counts[i]++
+			turns[d][i]++
+		}
+		for i, n := range counts {
+			if n != weights[i] {
+				t.Errorf("Slice %d: pool %d has %d devices, "+
+					"want %d", slice, i, n, weights[i])
+			}
+		}
+	}
+	for d := range turns {
+		for i, n := range turns[d] {
+			if n != weights[i] {
+				t.Errorf("Device %d mined for pool %d for %d of "+
+					"%d slices, want %d", d, i, n, total,
+					weights[i])
+			}
+		}
+	}
+	pm.Close()
+
+	// A miner balancing between the pools sends the share every device
+	// finds to the pool it mines for.  Shares carry the extranonce2 of
+	// the device, which is its index.
+	cfg = &config{
+		Algo:            autoPowAlgorithm,
+		Backend:         "sim",
+		SimDevices:      devices,
+		SimBits:         32,
+		PoolWorkTimeout: time.Minute,
+		PoolStrategy:    poolStrategyBalance,
+		pools:           configs,
+	}
+	m, err := NewMiner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan struct{})
+	go func() {
+		m.Run()
+		close(done)
+	}()
+	defer func() {
+		m.Stop()
+		<-done
+	}()
+	waitFor(t, "devices split", func() bool {
+		for d := range m.devices {
+			if m.pools.PoolFor(d) == nil {
+				return false
+			}
+		}
+		return true
+	})
+
+	var work Work
+	order := selectPowAlgorithm(work.Data[:]).ByteOrder()
+	owner := make(map[string]int)
+	want := make([]int, len(pools))
+	for d, dev := range m.devices {
+		for i, s := range m.pools.sessions {
+			if m.pools.PoolFor(d) == s {
+				var en2 [4]byte
+				order.PutUint32(en2[:], uint32(d))
+				owner[hex.EncodeToString(en2[:])] = i
+				want[i]++
+			}
+		}
+		dev.worker.(*simWorker).InjectSolution(uint32(0x1000 + d))
+	}
+	if want[0] != weights[0] || want[1] != weights[1] {
+		t.Fatalf("Pools have %v devices, want %v", want, weights)
+	}
+	for i, pool := range pools {
+		s := m.pools.sessions[i]
+		waitFor(t, "accepted shares", func() bool {
+			accepted, rejected := s.Shares()
+			return accepted >= want[i] && rejected == 0
+		})
+		shares := pool.submitted()
+		if accepted, _ := s.Shares(); accepted != len(shares) {
+			t.Errorf("Session of pool %d counted %d accepted "+
+				"shares, want %d", i, accepted, len(shares))
+		}
+		for _, share := range shares {
+			if j, ok := owner[share[2]]; !ok || j != i {
+				t.Errorf("Pool %d got share %v of a device of "+
+					"another pool", i, share)
+			}
+		}
+	}
+}
+
+// TestPoolFailoverConnect checks that the manager connects to the pool it
+// fails over to in the background, so that it keeps checking the pools while
+// the pool takes its time to answer, and that it fails over once the pool is
+// connected to.
+func TestPoolFailoverConnect(t *testing.T) {
+	primary := newMockPool(t, 1)
+
+	// The backup pool only answers once it is let go.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	backup := serveMockPool(t, &gatedListener{Listener: ln,
+		gate: make(chan struct{})}, 1)
+	gate := backup.ln.(*gatedListener).gate
+
+	configs := []*poolConfig{
+		{url: primary.url(), user: "user", pass: "pass", weight: 1},
+		{url: backup.url(), user: "user", pass: "pass", weight: 1},
+	}
+	// The manager is not started, so that it is updated here and not by
+	// its run goroutine.
+	pm := newPoolManager(configs, time.Minute, poolStrategyFailover, 1)
+	defer pm.Close()
+	if err := pm.connect(0); err != nil {
+		t.Fatal(err)
+	}
+
+	primary.close()
+	waitFor(t, "lost primary pool", func() bool {
+		return !pm.sessions[0].Connected()
+	})
+	start := time.Now()
+	pm.update()
+	if d := time.Since(start); d > time.Second {
+		t.Fatalf("Update took %v connecting to the backup pool", d)
+	}
+	if pm.current != 0 || pm.sessions[1] != nil || !pm.connecting {
+		t.Fatal("Not connecting to the backup pool in the background")
+	}
+
+	close(gate)
+	select {
+	case res := <-pm.connected:
+		if res.err != nil {
+			t.Fatal(res.err)
+		}
+		pm.connectDone(res)
+	case <-time.After(10 * time.Second):
+		t.Fatal("Backup pool was not connected to")
+	}
+	waitFor(t, "work from the backup pool", func() bool {
+		return pm.works(1)
+	})
+	pm.update()
+	if pm.current != 1 || pm.lastReason != "connection lost" {
+		t.Fatalf("On pool %d after %q, want pool 1 after a lost "+
+			"connection", pm.current, pm.lastReason)
+	}
+}
+
+// gatedListener is a listener whose connections are only accepted once gate
+// is closed.
+type gatedListener struct {
+	net.Listener
+	gate chan struct{}
+}
+
+func (l *gatedListener) Accept() (net.Conn, error) {
+	<-l.gate
+	return l.Listener.Accept()
+}
+
+// newTestCert returns a self-signed CA certificate for 127.0.0.1, which is
+// also written to a PEM file.
+func newTestCert(t *testing.T) (tls.Certificate, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "mock pool"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage: x509.KeyUsageDigitalSignature |
+			x509.KeyUsageCertSign,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl,
+		&key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(t.TempDir(), "pool.pem")
+	block := &pem.Block{Type: "CERTIFICATE", Bytes: der}
+	err = ioutil.WriteFile(file, pem.EncodeToMemory(block), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key},
+		file
+}
+
+// TestPoolTLS connects to a mock pool over TLS with the TLS settings a pool
+// can have and checks which of them trust the pool.
+func TestPoolTLS(t *testing.T) {
+	cert, certFile := newTestCert(t)
+	_, otherFile := newTestCert(t)
+	fingerprint := sha256.Sum256(cert.Certificate[0])
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0",
+		&tls.Config{Certificates: []tls.Certificate{cert}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := serveMockPool(t, ln, 1)
+	url := "stratum+ssl://" + ln.Addr().String()
+
+	tests := []struct {
+		name string
+		pc   poolConfig
+		err  string
+	}{
+		{"pinned CA", poolConfig{cert: certFile}, ""},
+		{"wrong CA", poolConfig{cert: otherFile}, "unknown authority"},
+		{"system roots", poolConfig{}, "unknown authority"},
+		{"fingerprint", poolConfig{fingerprint: fingerprint[:]}, ""},
+		{"fingerprint and wrong CA", poolConfig{cert: otherFile,
+			fingerprint: fingerprint[:]}, ""},
+		{"wrong fingerprint", poolConfig{skipVerify: true,
+			fingerprint: make([]byte, sha256.Size)}, "fingerprint"},
+		{"skip verify", poolConfig{skipVerify: true}, ""},
+	}
+	for n, test := range tests {
+		test.pc.url = url
+		tlsConfig, err := test.pc.tlsConfig()
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		s, err := newStratum(url, "user", "pass", tlsConfig)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		err = s.connect()
+		if err == nil {
+			defer s.Close()
+		}
+		switch {
+		case test.err == "" && err != nil:
+			t.Errorf("%s: %v", test.name, err)
+		case test.err != "" && err == nil:
+			t.Errorf("%s: connected to the pool", test.name)
+		case err != nil && !strings.Contains(err.Error(), test.err):
+			t.Errorf("%s: error %v does not mention %q", test.name,
+				err, test.err)
+		case err == nil:
+			// The session is subscribed on this connection.
+			s.workMtx.Lock()
+			en1 := s.PoolWork.ExtraNonce1
+			s.workMtx.Unlock()
+			if want := pool.extraNonce1(n); en1 != want {
+				t.Errorf("%s: extranonce1 is %s, want %s",
+					test.name, en1, want)
+			}
+		}
+	}
+}
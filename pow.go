@@ -0,0 +1,287 @@
+// Copyright (c) 2026 The Decred developers
+
+package main
+
+import (
+	"encoding/binary"
+
+	"github.com/decred/dcrd/wire"
+
+	"github.com/decred/gominer/blake256"
+	"github.com/decred/gominer/blake3"
+)
+
+// PowAlgorithm is a proof-of-work hash function along with the layout of the
+// work the workers search for it.  All algorithms hash the 180 byte header in
+// three 64 byte blocks, so the work is always a midstate of the first two
+// blocks and the final block as 16 message words with the nonces in the
+// nonce0Word, nonce1Word and nonce2Word words.
+type PowAlgorithm interface {
+	// Name returns the name of the algorithm used in the config.
+	Name() string
+
+	// ByteOrder returns the byte order message words are read in.  The
+	// nonces are stored in the header in this order so that the word a
+	// nonce is put in matches the serialized header.
+	ByteOrder() binary.ByteOrder
+
+	// Midstate returns the chaining value after hashing the first two
+	// blocks of the serialized header in data.
+	Midstate(data []byte) [8]uint32
+
+	// LastBlock returns the final block of the serialized header in data
+	// as message words, padding included.
+	LastBlock(data []byte) [16]uint32
+
+	// FinalBlock hashes the final block with the given nonce0 starting
+	// from the midstate and returns the hash as words.  Word 7 holds the
+	// most significant bytes of the hash, which is what the workers test,
+	// in the byte order of the algorithm.  hashHighBits reads them as a
+	// number.
+	FinalBlock(midstate *[8]uint32, lastBlock *[16]uint32, nonce0 uint32) [8]uint32
+
+	// Hash returns the proof-of-work hash of a serialized header.
+	Hash(header []byte) [32]byte
+
+	// KernelSource returns the name of the embedded OpenCL search kernel
+	// or the file with the kernel.
+	KernelSource() string
+}
+
+var (
+	// blake256Pow is the original 14 round BLAKE-256 proof of work.
+	blake256Pow PowAlgorithm = blake256Algorithm{}
+
+	// blake3Pow is the BLAKE3 proof of work introduced by DCP-0011.
+	blake3Pow PowAlgorithm = blake3Algorithm{}
+
+	// powAlgorithms lists every supported algorithm, oldest first.
+	powAlgorithms = []PowAlgorithm{blake256Pow, blake3Pow}
+)
+
+// autoPowAlgorithm is the --algo value selecting the algorithm from the
+// activation rules of the network.
+const autoPowAlgorithm = "auto"
+
+// headerHeightOffset is the offset of the block height in a serialized
+// header.
+const headerHeightOffset = 128
+
+// hashHighBits returns the most significant 32 bits of the hash whose words
+// FinalBlock of the algorithm returned, with the hash taken as the little
+// endian number it is compared to targets as.
+func hashHighBits(algo PowAlgorithm, h *[8]uint32) uint32 {
+	var b [4]byte
+	algo.ByteOrder().PutUint32(b[:], h[7])
+	return binary.LittleEndian.Uint32(b[:])
+}
+
+// powAlgorithmNames returns the names of the supported algorithms.
+func powAlgorithmNames() []string {
+	names := make([]string, 0, len(powAlgorithms))
+	for _, algo := range powAlgorithms {
+		names = append(names, algo.Name())
+	}
+	return names
+}
+
+// powAlgorithmByName returns the algorithm with the given config name.
+func powAlgorithmByName(name string) (PowAlgorithm, bool) {
+	for _, algo := range powAlgorithms {
+		if algo.Name() == name {
+			return algo, true
+		}
+	}
+	return nil, false
+}
+
+// powActivation is the rule switching a network over to an algorithm.  The
+// algorithm applies to blocks with at least the given header version and
+// height.
+type powActivation struct {
+	algorithm PowAlgorithm
+	version   int32
+	height    uint32
+}
+
+// Header versions and heights from which blocks are hashed with BLAKE3.  The
+// version was bumped when the agenda was put to a vote, so on the main network
+// only the height of the activation is conclusive.  The test networks use a
+// block version one higher than the main network and do not have a fixed
+// activation height.
+const (
+	blake3MainNetVersion = 10
+	blake3MainNetHeight  = 794368
+	blake3TestNetVersion = 11
+)
+
+// powActivations returns the activation rules of the selected network, oldest
+// first.  Blocks matching no rule use BLAKE-256.
+func powActivations() []powActivation {
+	blake3Rule := powActivation{
+		algorithm: blake3Pow,
+		version:   blake3MainNetVersion,
+		height:    blake3MainNetHeight,
+	}
+	if cfg.TestNet || cfg.SimNet {
+		blake3Rule.version = blake3TestNetVersion
+		blake3Rule.height = 0
+	}
+	if cfg.Blake3Height != 0 {
+		blake3Rule.height = cfg.Blake3Height
+	}
+	return []powActivation{blake3Rule}
+}
+
+// selectPowAlgorithm returns the algorithm used to mine the serialized header
+// in data.  The algorithm set with --algo always wins.  Otherwise it is picked
+// from the header version and height according to the activation rules of the
+// network.
+func selectPowAlgorithm(data []byte) PowAlgorithm {
+	if algo, ok := powAlgorithmByName(cfg.Algo); ok {
+		return algo
+	}
+
+	version := int32(binary.LittleEndian.Uint32(data[0:4]))
+	height := binary.LittleEndian.Uint32(data[headerHeightOffset:])
+	algo := blake256Pow
+	for _, rule := range powActivations() {
+		if version >= rule.version && height >= rule.height {
+			algo = rule.algorithm
+		}
+	}
+	return algo
+}
+
+// configuredPowAlgorithms returns the algorithms the miner may be asked to
+// mine, which is every algorithm unless one was set with --algo.
+func configuredPowAlgorithms() []PowAlgorithm {
+	if algo, ok := powAlgorithmByName(cfg.Algo); ok {
+		return []PowAlgorithm{algo}
+	}
+	return powAlgorithms
+}
+
+// blake256Algorithm implements PowAlgorithm for BLAKE-256.
+type blake256Algorithm struct{}
+
+// Name returns the name of the algorithm.
+func (blake256Algorithm) Name() string {
+	return "blake256"
+}
+
+// ByteOrder returns big endian, the byte order of BLAKE-256 message words.
+func (blake256Algorithm) ByteOrder() binary.ByteOrder {
+	return binary.BigEndian
+}
+
+// Midstate returns the BLAKE-256 chaining value after the first two blocks.
+func (blake256Algorithm) Midstate(data []byte) [8]uint32 {
+	return blake256.NewMidstate(data[0 : 2*blake256.BlockSize]).H
+}
+
+// LastBlock returns the final header block with its BLAKE-256 padding.  Work
+// from the various sources does not agree on what follows the header so the
+// padding is always rebuilt.
+func (blake256Algorithm) LastBlock(data []byte) [16]uint32 {
+	var block [blake256.BlockSize]byte
+	n := copy(block[:], data[2*blake256.BlockSize:wire.MaxBlockHeaderPayload])
+	block[n] = 0x80
+	block[55] |= 0x01
+	binary.BigEndian.PutUint64(block[56:], headerBits)
+
+	var m [16]uint32
+	for i := range m {
+		m[i] = binary.BigEndian.Uint32(block[i*4:])
+	}
+	return m
+}
+
+// FinalBlock hashes the final block with BLAKE-256.
+func (blake256Algorithm) FinalBlock(midstate *[8]uint32, lastBlock *[16]uint32, nonce0 uint32) [8]uint32 {
+	var block [blake256.BlockSize]byte
+	for i, v := range lastBlock {
+		binary.BigEndian.PutUint32(block[i*4:], v)
+	}
+	binary.BigEndian.PutUint32(block[nonce0Word*4:], nonce0)
+	h := *midstate
+	blake256.Block(h[:], block[:], headerBits)
+	return h
+}
+
+// Hash returns the BLAKE-256 hash of the header.
+func (blake256Algorithm) Hash(header []byte) [32]byte {
+	return blake256.Sum256(header)
+}
+
+// KernelSource returns the BLAKE-256 kernel.
+func (blake256Algorithm) KernelSource() string {
+	return cfg.ClKernel
+}
+
+// blake3Algorithm implements PowAlgorithm for BLAKE3.
+type blake3Algorithm struct{}
+
+// headerBlake3Len is the number of header bytes in the final BLAKE3 block.
+const headerBlake3Len = wire.MaxBlockHeaderPayload - 2*blake3.BlockSize
+
+// Name returns the name of the algorithm.
+func (blake3Algorithm) Name() string {
+	return "blake3"
+}
+
+// ByteOrder returns little endian, the byte order of BLAKE3 message words.
+func (blake3Algorithm) ByteOrder() binary.ByteOrder {
+	return binary.LittleEndian
+}
+
+// Midstate returns the BLAKE3 chaining value after the first two blocks of
+// the single chunk the header fits in.
+func (blake3Algorithm) Midstate(data []byte) [8]uint32 {
+	var m [16]uint32
+	h := blake3.IV
+	for i := 0; i < 2; i++ {
+		block := data[i*blake3.BlockSize:]
+		for j := range m {
+			m[j] = binary.LittleEndian.Uint32(block[j*4:])
+		}
+		var flags uint32
+		if i == 0 {
+			flags = blake3.FlagChunkStart
+		}
+		blake3.Block(&h, &m, 0, blake3.BlockSize, flags)
+	}
+	return h
+}
+
+// LastBlock returns the final header block, which BLAKE3 pads with zeros.
+func (blake3Algorithm) LastBlock(data []byte) [16]uint32 {
+	var block [blake3.BlockSize]byte
+	copy(block[:], data[2*blake3.BlockSize:wire.MaxBlockHeaderPayload])
+
+	var m [16]uint32
+	for i := range m {
+		m[i] = binary.LittleEndian.Uint32(block[i*4:])
+	}
+	return m
+}
+
+// FinalBlock hashes the final block with BLAKE3 as the root of the tree.
+func (blake3Algorithm) FinalBlock(midstate *[8]uint32, lastBlock *[16]uint32, nonce0 uint32) [8]uint32 {
+	m := *lastBlock
+	m[nonce0Word] = nonce0
+	h := *midstate
+	blake3.Block(&h, &m, 0, headerBlake3Len,
+		blake3.FlagChunkEnd|blake3.FlagRoot)
+	return h
+}
+
+// Hash returns the BLAKE3 hash of the header.
+func (blake3Algorithm) Hash(header []byte) [32]byte {
+	return blake3.Sum256(header)
+}
+
+// KernelSource returns the BLAKE3 kernel.
+func (blake3Algorithm) KernelSource() string {
+	return cfg.ClKernelBlake3
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2026 The Decred developers
+
+package main
+
+import (
+	"encoding/binary"
+	"math/rand"
+	"testing"
+)
+
+// TestPowFinalBlock checks that hashing the final block from the midstate
+// gives the hash of the whole header for every algorithm.
+func TestPowFinalBlock(t *testing.T) {
+	cfg = &config{Algo: autoPowAlgorithm}
+	r := rand.New(rand.NewSource(1))
+	for _, algo := range powAlgorithms {
+		for i := 0; i < 50; i++ {
+			var data [192]byte
+			r.Read(data[:headerBits/8])
+			midstate := algo.Midstate(data[:])
+			lastBlock := algo.LastBlock(data[:])
+			nonce := r.Uint32()
+			got := algo.FinalBlock(&midstate, &lastBlock, nonce)
+
+			order := algo.ByteOrder()
+			order.PutUint32(data[128+nonce0Word*4:], nonce)
+			hash := algo.Hash(data[:headerBits/8])
+			for j := range got {
+				if want := order.Uint32(hash[j*4:]); got[j] != want {
+					t.Fatalf("%s: word %d of the final block "+
+						"hash is %08x, want %08x", algo.Name(),
+						j, got[j], want)
+				}
+			}
+		}
+	}
+}
+
+// TestSelectPowAlgorithm checks the activation rules of every network at
+// their boundaries.
+func TestSelectPowAlgorithm(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config
+		version uint32
+		height  uint32
+		want    PowAlgorithm
+	}{
+		{"mainnet before activation", config{},
+			blake3MainNetVersion, blake3MainNetHeight - 1, blake256Pow},
+		{"mainnet at activation", config{},
+			blake3MainNetVersion, blake3MainNetHeight, blake3Pow},
+		{"mainnet after activation", config{},
+			blake3MainNetVersion + 1, blake3MainNetHeight + 1, blake3Pow},
+		{"mainnet old version", config{},
+			blake3MainNetVersion - 1, blake3MainNetHeight + 1, blake256Pow},
+		{"testnet new version", config{TestNet: true},
+			blake3TestNetVersion, 1, blake3Pow},
+		{"testnet old version", config{TestNet: true},
+			blake3TestNetVersion - 1, blake3MainNetHeight, blake256Pow},
+		{"simnet new version", config{SimNet: true},
+			blake3TestNetVersion, 0, blake3Pow},
+		{"height override before", config{Blake3Height: 1000},
+			blake3MainNetVersion, 999, blake256Pow},
+		{"height override at", config{Blake3Height: 1000},
+			blake3MainNetVersion, 1000, blake3Pow},
+		{"testnet height override", config{TestNet: true, Blake3Height: 1000},
+			blake3TestNetVersion, 999, blake256Pow},
+		{"algo override blake256", config{Algo: "blake256"},
+			blake3MainNetVersion, blake3MainNetHeight, blake256Pow},
+		{"algo override blake3", config{Algo: "blake3"},
+			1, 1, blake3Pow},
+	}
+
+	for _, test := range tests {
+		c := test.cfg
+		if c.Algo == "" {
+			c.Algo = autoPowAlgorithm
+		}
+		cfg = &c
+
+		var data [192]byte
+		binary.LittleEndian.PutUint32(data[0:], test.version)
+		binary.LittleEndian.PutUint32(data[headerHeightOffset:],
+			test.height)
+		got := selectPowAlgorithm(data[:])
+		if got != test.want {
+			t.Errorf("%s: got %s, want %s", test.name, got.Name(),
+				test.want.Name())
+		}
+	}
+}
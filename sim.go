@@ -0,0 +1,200 @@
+// Copyright (c) 2026 The Decred developers
+
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// simBatchSize is the number of nonces a sim worker hashes for every call to
+// Search.  The nonces really are hashed, so it is kept small.
+const simBatchSize = 1 << 16
+
+func init() {
+	registerBackend("sim", newSimBackend)
+}
+
+// simBackend is a Backend of simulated devices for tests and demos.  Its
+// workers hash on the cpu like the cpu backend, but report every nonce whose
+// hash has a configurable number of leading zero bits, so that valid shares
+// are found in seconds at very low targets.  They can be throttled to a fixed
+// hashrate and made to fail at random using a seeded, and therefore
+// reproducible, random source.
+type simBackend struct{}
+
+func newSimBackend() (Backend, error) {
+	return &simBackend{}, nil
+}
+
+// Name returns the name of the sim backend.
+func (b *simBackend) Name() string {
+	return "sim"
+}
+
+// Workers returns the configured number of simulated workers.
+func (b *simBackend) Workers() ([]Worker, error) {
+	workers := make([]Worker, cfg.SimDevices)
+	for i := range workers {
+		workers[i] = newSimWorker(i, cfg.SimSeed+int64(i))
+	}
+	return workers, nil
+}
+
+// simWorker is a simulated device.
+type simWorker struct {
+	index     int
+	rand      *rand.Rand
+	algo      PowAlgorithm
+	midstate  [8]uint32
+	lastBlock [16]uint32
+
+	mtx      sync.Mutex
+	injected []uint32
+
+	// quit is closed by Interrupt.
+	quit     chan struct{}
+	quitOnce sync.Once
+}
+
+func newSimWorker(index int, seed int64) *simWorker {
+	return &simWorker{
+		index: index,
+		rand:  rand.New(rand.NewSource(seed)),
+		quit:  make(chan struct{}),
+	}
+}
+
+// simError is a simulated worker failure.  It is temporary, like the out of
+// resources errors of real devices, so the device retries after it.
+type simError struct {
+	worker string
+}
+
+// Error returns the error as a string.
+func (e simError) Error() string {
+	return fmt.Sprintf("Simulated failure on %s", e.worker)
+}
+
+// Temporary returns true since simulated failures go away on a retry.
+func (e simError) Temporary() bool {
+	return true
+}
+
+// Name returns a description of the worker.
+func (w *simWorker) Name() string {
+	return fmt.Sprintf("Sim device #%d", w.index)
+}
+
+// BatchSize returns the number of nonces hashed for each call to Search.
+func (w *simWorker) BatchSize() uint32 {
+	return simBatchSize
+}
+
+// SetWork stores the algorithm, the midstate and the final block for the
+// following searches.
+func (w *simWorker) SetWork(algo PowAlgorithm, midstate *[8]uint32, lastBlock *[16]uint32) error {
+	w.algo = algo
+	w.midstate = *midstate
+	w.lastBlock = *lastBlock
+	return nil
+}
+
+// InjectSolution makes the next search report the passed nonces in addition
+// to the ones it finds.  It is safe for concurrent use, so tests can inject
+// nonces into the workers of a running miner.
+func (w *simWorker) InjectSolution(nonces ...uint32) {
+	w.mtx.Lock()
+	w.injected = append(w.injected, nonces...)
+	w.mtx.Unlock()
+}
+
+// Interrupt cuts short a simulated stall or throttled search, and makes every
+// later one return right away.
+func (w *simWorker) Interrupt() {
+	w.quitOnce.Do(func() {
+		close(w.quit)
+	})
+}
+
+// sleep waits for d or until the worker is interrupted.
+func (w *simWorker) sleep(d time.Duration) {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+	case <-w.quit:
+	}
+}
+
+// chance returns true with the given probability.
+func (w *simWorker) chance(p float64) bool {
+	return p > 0 && w.rand.Float64() < p
+}
+
+// Search hashes the count nonces starting at start and returns those whose
+// hash has at least --simbits leading zero bits, along with any injected
+// nonces.  Depending on the configured failure rates it may instead return a
+// temporary error, hang for a while first or add a nonce that does not solve
+// the work.
+func (w *simWorker) Search(start, count uint32) ([]uint32, uint64, error) {
+	began := time.Now()
+
+	if w.chance(cfg.SimErrorRate) {
+		return nil, 0, simError{w.Name()}
+	}
+	if w.chance(cfg.SimStallRate) {
+		minrLog.Debugf("%s: simulating a stall of %v", w.Name(),
+			cfg.SimStall)
+		w.sleep(cfg.SimStall)
+	}
+
+	var candidates []uint32
+	mask := ^uint32(0) << (32 - uint(cfg.SimBits))
+	for i := uint32(0); i < count; i++ {
+		nonce := start + i
+		h := w.algo.FinalBlock(&w.midstate, &w.lastBlock, nonce)
+		if hashHighBits(w.algo, &h)&mask == 0 {
+			candidates = append(candidates, nonce)
+		}
+	}
+
+	w.mtx.Lock()
+	candidates = append(candidates, w.injected...)
+	w.injected = nil
+	w.mtx.Unlock()
+
+	if w.chance(cfg.SimBadHashRate) {
+		candidates = append(candidates, w.badNonce())
+	}
+
+	// Sleep off the rest of the time the batch takes at the configured
+	// hashrate.
+	if cfg.SimHashrate > 0 {
+		want := time.Duration(float64(count) / cfg.SimHashrate *
+			float64(time.Second))
+		if wait := want - time.Since(began); wait > 0 {
+			w.sleep(wait)
+		}
+	}
+
+	return candidates, uint64(count), nil
+}
+
+// badNonce returns a random nonce that does not solve the current work.  Its
+// hash has the top bit set, which puts it above the proof-of-work limit of
+// every network.
+func (w *simWorker) badNonce() uint32 {
+	for {
+		nonce := w.rand.Uint32()
+		h := w.algo.FinalBlock(&w.midstate, &w.lastBlock, nonce)
+		if hashHighBits(w.algo, &h)&0x80000000 != 0 {
+			return nonce
+		}
+	}
+}
+
+// Release is a no-op since the sim worker holds no external resources.
+func (w *simWorker) Release() {}
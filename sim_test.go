@@ -0,0 +1,170 @@
+// Copyright (c) 2026 The Decred developers
+
+package main
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/wire"
+)
+
+// runSimDevice starts mining work on a device with a sim worker, whose target
+// every hash meets, and returns the device, its worker, the channel of its
+// solutions and a channel that is closed when Run returns.
+func runSimDevice() (*Device, *simWorker, chan *Work, chan struct{}) {
+	w := newSimWorker(0, 1)
+	solved := make(chan *Work, 10)
+	d := NewDevice(0, w, solved)
+
+	var work Work
+	for i := range work.Target {
+		work.Target[i] = 0xff
+	}
+	work.Algorithm = selectPowAlgorithm(work.Data[:])
+
+	done := make(chan struct{})
+	go func() {
+		d.Run()
+		close(done)
+	}()
+	d.SetWork(&work)
+	return d, w, solved, done
+}
+
+// waitSolution waits for the device to report the nonce.
+func waitSolution(t *testing.T, solved chan *Work, nonce uint32) {
+	t.Helper()
+	timeout := time.After(10 * time.Second)
+	for {
+		select {
+		case work := <-solved:
+			order := work.Algorithm.ByteOrder()
+			if order.Uint32(work.Data[128+4*nonce0Word:]) == nonce {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("Nonce %d was not reported", nonce)
+		}
+	}
+}
+
+// waitDone fails the test unless the device stops within ten seconds.
+func waitDone(t *testing.T, done chan struct{}) {
+	t.Helper()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Device did not stop")
+	}
+}
+
+// TestSimInjectSolution checks that injected nonces are reported by a running
+// device, including when searches keep failing with temporary errors.
+func TestSimInjectSolution(t *testing.T) {
+	defer func(delay time.Duration) {
+		deviceRetryDelay = delay
+	}(deviceRetryDelay)
+	deviceRetryDelay = time.Millisecond
+
+	tests := []struct {
+		name      string
+		errorRate float64
+	}{
+		{"no errors", 0},
+		{"errors", 0.3},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg = &config{Algo: autoPowAlgorithm, SimBits: 32,
+				SimErrorRate: test.errorRate}
+			d, w, solved, done := runSimDevice()
+			for nonce := uint32(1); nonce <= 10; nonce++ {
+				w.InjectSolution(nonce)
+				waitSolution(t, solved, nonce)
+			}
+			d.Stop()
+			waitDone(t, done)
+		})
+	}
+}
+
+// TestSimSearch brute-forces nonces at a low target with a sim worker and
+// checks, by hashing the whole header with each nonce, that it reports exactly
+// the nonces whose hash is below the target --simbits sets.
+func TestSimSearch(t *testing.T) {
+	const simBits = 8
+	cfg = &config{Algo: autoPowAlgorithm, SimBits: simBits}
+	target := new(big.Int).Lsh(big.NewInt(1), 256-simBits)
+
+	r := rand.New(rand.NewSource(1))
+	for _, algo := range powAlgorithms {
+		var data [192]byte
+		r.Read(data[:wire.MaxBlockHeaderPayload])
+		midstate := algo.Midstate(data[:])
+		lastBlock := algo.LastBlock(data[:])
+		w := newSimWorker(0, 1)
+		err := w.SetWork(algo, &midstate, &lastBlock)
+		if err != nil {
+			t.Fatalf("%s: %v", algo.Name(), err)
+		}
+		nonces, hashes, err := w.Search(0, simBatchSize)
+		if err != nil || hashes != simBatchSize {
+			t.Fatalf("%s: searched %d nonces: %v", algo.Name(),
+				hashes, err)
+		}
+
+		reported := make(map[uint32]bool)
+		for _, nonce := range nonces {
+			reported[nonce] = true
+		}
+		found := 0
+		for nonce := uint32(0); nonce < simBatchSize; nonce++ {
+			algo.ByteOrder().PutUint32(data[128+4*nonce0Word:], nonce)
+			hash := algo.Hash(data[:wire.MaxBlockHeaderPayload])
+			hashNum := new(big.Int).SetBytes(reverse(hash[:]))
+			solves := hashNum.Cmp(target) < 0
+			if solves != reported[nonce] {
+				t.Fatalf("%s: nonce %d with hash %064x is "+
+					"reported %v", algo.Name(), nonce, hashNum,
+					reported[nonce])
+			}
+			if solves {
+				found++
+			}
+		}
+		if found != len(nonces) || found == 0 {
+			t.Fatalf("%s: %d nonces reported, %d solve the work",
+				algo.Name(), len(nonces), found)
+		}
+	}
+}
+
+// TestSimErrors checks that simulated failures are temporary and that a
+// device gives up on a worker that fails every time.
+func TestSimErrors(t *testing.T) {
+	defer func(delay time.Duration) {
+		deviceRetryDelay = delay
+	}(deviceRetryDelay)
+	deviceRetryDelay = time.Millisecond
+
+	if !isTemporary(simError{"Sim device #0"}) {
+		t.Fatal("Simulated failures are not temporary")
+	}
+
+	cfg = &config{Algo: autoPowAlgorithm, SimBits: 32, SimErrorRate: 1}
+	_, _, _, done := runSimDevice()
+	waitDone(t, done)
+}
+
+// TestSimStall checks that stopping a device cuts short a stalled search.
+func TestSimStall(t *testing.T) {
+	cfg = &config{Algo: autoPowAlgorithm, SimBits: 32, SimStallRate: 1,
+		SimStall: time.Hour}
+	d, _, _, done := runSimDevice()
+	time.Sleep(50 * time.Millisecond)
+	d.Stop()
+	waitDone(t, done)
+}
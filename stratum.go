@@ -6,6 +6,7 @@ import (
 	"bufio"
 	"bytes"
 	"crypto/rand"
+	"crypto/tls"
 	"encoding/binary"
 	"encoding/hex"
 	"encoding/json"
@@ -14,9 +15,9 @@ import (
 	"io"
 	"math/big"
 	"net"
-	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/davecgh/go-spew/spew"
@@ -27,19 +28,58 @@ import (
 // Stratum holds all the shared information for a stratum connection.
 // XXX most of these should be unexported and use getters/setters.
 type Stratum struct {
-	Pool      string
-	User      string
-	Pass      string
-	Conn      net.Conn
-	Reader    *bufio.Reader
-	ID        uint64
-	authID    uint64
-	subID     uint64
-	submitID  uint64
-	Diff      float64
-	Target    string
-	submitted bool
-	PoolWork  NotifyWork
+	Pool string
+	User string
+	Pass string
+
+	// Diff is the share difficulty set by the pool, Target its share
+	// target and PoolWork the work it sent.  They are protected by
+	// workMtx, which GetPoolWork holds while it builds work from them.
+	Diff     float64
+	Target   string
+	PoolWork NotifyWork
+	workMtx  sync.Mutex
+
+	// reconnectWait is how long to wait before connecting again when
+	// the pool asked to be reconnected to.  The listener sets it before
+	// it returns, after which the supervisor uses it.
+	reconnectWait time.Duration
+
+	// Conn is the connection to the pool, which is nil while
+	// disconnected, and Reader reads from it.  ready is set once the
+	// worker is subscribed and authorized on Conn.  They are protected
+	// by connMtx, which also keeps the messages written to Conn whole.
+	Conn    net.Conn
+	Reader  *bufio.Reader
+	ready   bool
+	connMtx sync.Mutex
+
+	// sessionStart is when the current connection was set up and
+	// lastNotify when the pool last sent work on it, or zero when it has
+	// not yet.  They are protected by connMtx.
+	sessionStart time.Time
+	lastNotify   time.Time
+
+	// accepted and rejected count the shares the pool accepted and
+	// rejected.  They are protected by sharesMtx.
+	accepted  int
+	rejected  int
+	sharesMtx sync.Mutex
+
+	// tlsConfig holds the TLS settings for pools connected to over TLS,
+	// and is nil for the others.
+	tlsConfig *tls.Config
+
+	// quit is closed by Close.
+	quit      chan struct{}
+	closeOnce sync.Once
+
+	// ID is the id of the last request sent.  The requests waiting for
+	// their reply are in pending, by id.  Both are protected by
+	// pendingMtx.
+	ID         uint64
+	pending    map[uint64]*stratumRequest
+	pendingMtx sync.Mutex
 }
 
 // NotifyWork holds all the info recieved from a mining.notify message along
@@ -62,6 +102,16 @@ type NotifyWork struct {
 	Work              *Work
 }
 
+// stratumJob is the pool job work was built from, which its solutions are
+// submitted for: the job ID and ntime sent by the pool and where the
+// extranonce2 is in the header.
+type stratumJob struct {
+	ID               string
+	Ntime            string
+	ExtraNonce2Start int
+	ExtraNonce2End   int
+}
+
 // StratumMsg is the basic message object from stratum.
 type StratumMsg struct {
 	Method string `json:"method"`
@@ -95,6 +145,7 @@ type BasicReply struct {
 
 // SubscribeReply models the server response to a subscribe message.
 type SubscribeReply struct {
+	ID                interface{}
 	SubscribeID       string
 	ExtraNonce1       string
 	ExtraNonce2Length float64
@@ -124,118 +175,123 @@ type Submit struct {
 var errJsonType = errors.New("Unexpected type in json.")
 
 // StratumConn starts the initial connection to a stratum pool and sets defaults
-// in the pool object.
+// in the pool object.  Once subscribed and authorized, the connection is kept
+// up in the background, reconnecting whenever it is lost, until Close is
+// called.
 func StratumConn(pool, user, pass string) (*Stratum, error) {
-	poolLog.Infof("Using pool: %v", pool)
+	stratum, err := newStratum(pool, user, pass, nil)
+	if err != nil {
+		return nil, err
+	}
+	err = stratum.connect()
+	if err != nil {
+		return nil, err
+	}
+	return stratum, nil
+}
+
+// stratumScheme returns the scheme of a stratum pool URL and whether the pool
+// is connected to over TLS, or false when it is not a stratum pool URL.
+func stratumScheme(pool string) (string, bool, bool) {
+	for _, scheme := range []string{"stratum+ssl://", "stratum+tls://"} {
+		if strings.HasPrefix(pool, scheme) {
+			return scheme, true, true
+		}
+	}
 	proto := "stratum+tcp://"
-	if strings.HasPrefix(pool, proto) {
+	return proto, false, strings.HasPrefix(pool, proto)
+}
+
+// newStratum returns the pool object for a stratum pool without connecting
+// to it.  Pools with a TLS scheme are connected to with tlsConfig, or with
+// the default TLS settings when it is nil.
+func newStratum(pool, user, pass string, tlsConfig *tls.Config) (*Stratum, error) {
+	poolLog.Infof("Using pool: %v", pool)
+	proto, useTLS, ok := stratumScheme(pool)
+	if ok {
 		pool = strings.Replace(pool, proto, "", 1)
 	} else {
 		err := errors.New("Only stratum pools supported.")
 		return nil, err
 	}
-	conn, err := net.Dial("tcp", pool)
-	if err != nil {
-		return nil, err
-	}
 	var stratum Stratum
-	stratum.ID = 1
-	stratum.Conn = conn
+	if useTLS {
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{}
+		}
+		stratum.tlsConfig = tlsConfig.Clone()
+		stratum.tlsConfig.ServerName = poolHost(pool)
+	}
 	stratum.Pool = pool
 	stratum.User = user
 	stratum.Pass = pass
-	// We will set it for sure later but this really should be the value and
-	// setting it here will prevent so incorrect matches based on the
-	// default 0 value.
-	stratum.authID = 2
+	stratum.quit = make(chan struct{})
 	// Target for share is 1 unless we hear otherwise.
 	stratum.Diff = 1
 	stratum.Target = stratum.diffToTarget(stratum.Diff)
 	stratum.PoolWork.NewWork = false
-	stratum.Reader = bufio.NewReader(stratum.Conn)
-	go stratum.Listen()
-
-	err = stratum.Subscribe()
-	if err != nil {
-		return nil, err
-	}
-	// Should NOT need this.
-	//time.Sleep(5 * time.Second)
-	err = stratum.Auth()
-	if err != nil {
-		return nil, err
-	}
-
 	return &stratum, nil
 }
 
-// Reconnect reconnects to a stratum server if the connection has been lost.
-func (s *Stratum) Reconnect() error {
-	conn, err := net.Dial("tcp", s.Pool)
-	if err != nil {
-		return err
-	}
-	s.Conn = conn
-	s.Reader = bufio.NewReader(s.Conn)
-	err = s.Subscribe()
-	if err != nil {
-		return nil
-	}
-	// Should NOT need this.
-	time.Sleep(5 * time.Second)
-	// XXX Do I really need to re-auth here?
-	err = s.Auth()
-	if err != nil {
-		return nil
-	}
-	return nil
-}
-
-// Listen is the listener for the incoming messages from the stratum pool.
+// Listen is the listener for the incoming messages from the stratum pool.  It
+// returns when the connection is lost.
 func (s *Stratum) Listen() {
 	poolLog.Debug("Starting Listener")
 
+	s.connMtx.Lock()
+	conn, reader := s.Conn, s.Reader
+	s.connMtx.Unlock()
+	if conn == nil {
+		return
+	}
+
 	for {
-		result, err := s.Reader.ReadString('\n')
+		// A connection that was lost without being closed, such as
+		// when the pool host went away, never gets an error of its
+		// own, so a pool that has been quiet for too long is taken
+		// to be gone.
+		err := conn.SetReadDeadline(time.Now().Add(stratumIdleTimeout))
 		if err != nil {
-			if err == io.EOF {
+			poolLog.Errorf("Connection lost: %v", err)
+			return
+		}
+		result, err := reader.ReadString('\n')
+		if err != nil {
+			select {
+			case <-s.quit:
+				return
+			default:
+			}
+			var netErr net.Error
+			switch {
+			case err == io.EOF:
 				poolLog.Error("Connection lost!  Reconnecting.")
-				err = s.Reconnect()
-				if err != nil {
-					poolLog.Error(err)
-					poolLog.Error("Reconnect failed.")
-					os.Exit(1)
-					return
-				}
-
-			} else {
-				poolLog.Error(err)
+			case errors.As(err, &netErr) && netErr.Timeout():
+				poolLog.Errorf("No messages from the pool in "+
+					"%v!  Reconnecting.", stratumIdleTimeout)
+			default:
+				poolLog.Errorf("Connection lost: %v!  "+
+					"Reconnecting.", err)
 			}
-			continue
+			return
 		}
 		poolLog.Debug(strings.TrimSuffix(result, "\n"))
 		resp, err := s.Unmarshal([]byte(result))
 		if err != nil {
+			var rErr *replyError
+			if errors.As(err, &rErr) {
+				s.complete(rErr.id, nil, rErr.err)
+				continue
+			}
 			poolLog.Error(err)
 			continue
 		}
 		switch resp.(type) {
 		case *BasicReply:
 			aResp := resp.(*BasicReply)
-			if int(aResp.ID.(uint64)) == int(s.authID) {
-				if aResp.Result {
-					poolLog.Info("Logged in")
-				} else {
-					poolLog.Error("Auth failure.")
-				}
-			}
-			if aResp.ID == s.submitID {
-				if aResp.Result {
-					poolLog.Info("Share Accepted")
-				} else {
-					poolLog.Error("Share rejected: ", aResp.Error.ErrStr)
-				}
-				s.submitted = false
+			if !s.complete(aResp.ID.(uint64), aResp, nil) {
+				poolLog.Debugf("Reply to request %v came too late",
+					aResp.ID)
 			}
 		case StratumMsg:
 			nResp := resp.(StratumMsg)
@@ -252,17 +308,16 @@ func (s *Stratum) Listen() {
 					poolLog.Error(err)
 					continue
 				}
-				time.Sleep(time.Duration(wait) * time.Second)
 				pool := nResp.Params[0] + ":" + nResp.Params[1]
 				s.Pool = pool
-				err = s.Reconnect()
-				if err != nil {
-					poolLog.Error(err)
-					// XXX should just die at this point
-					// but we don't really have access to
-					// the channel to end everything.
-					return
+				if s.tlsConfig != nil {
+					s.tlsConfig = s.tlsConfig.Clone()
+					s.tlsConfig.ServerName = nResp.Params[0]
 				}
+				// The supervisor connects to the new pool
+				// after the wait once the listener is gone.
+				s.reconnectWait = time.Duration(wait) * time.Second
+				return
 			case "client.get_version":
 				poolLog.Debug("get_version request received.")
 				msg := StratumMsg{
@@ -270,17 +325,7 @@ func (s *Stratum) Listen() {
 					ID:     nResp.ID,
 					Params: []string{"decred-gominer/" + version()},
 				}
-				m, err := json.Marshal(msg)
-				if err != nil {
-					poolLog.Error(err)
-					continue
-				}
-				_, err = s.Conn.Write(m)
-				if err != nil {
-					poolLog.Error(err)
-					continue
-				}
-				_, err = s.Conn.Write([]byte("\n"))
+				err = s.send(msg)
 				if err != nil {
 					poolLog.Error(err)
 					continue
@@ -288,6 +333,7 @@ func (s *Stratum) Listen() {
 			}
 		case NotifyRes:
 			nResp := resp.(NotifyRes)
+			s.workMtx.Lock()
 			s.PoolWork.JobID = nResp.JobID
 			s.PoolWork.CB1 = nResp.GenTX1
 			//poolLog.Trace("CB1: " + spew.Sdump(s.PoolWork.CB1))
@@ -311,75 +357,96 @@ func (s *Stratum) Listen() {
 			s.PoolWork.NtimeDelta = parsedNtime - time.Now().Unix()
 			s.PoolWork.Clean = nResp.CleanJobs
 			s.PoolWork.NewWork = true
+			s.workMtx.Unlock()
+			s.connMtx.Lock()
+			s.lastNotify = time.Now()
+			s.connMtx.Unlock()
 			poolLog.Trace("notify: ", spew.Sdump(nResp))
 		case *SubscribeReply:
 			nResp := resp.(*SubscribeReply)
-			s.PoolWork.ExtraNonce1 = nResp.ExtraNonce1
-			s.PoolWork.ExtraNonce2Length = nResp.ExtraNonce2Length
-			poolLog.Info("Subscribe reply received.")
-			poolLog.Trace(spew.Sdump(resp))
+			if !s.complete(nResp.ID.(uint64), nResp, nil) {
+				poolLog.Debugf("Reply to request %v came too late",
+					nResp.ID)
+			}
 		default:
 			poolLog.Info("Unhandled message: ", result)
 		}
 	}
 }
 
-// Auth sends a message to the pool to authorize a worker.
+// Auth sends a message to the pool to authorize a worker and waits for the
+// reply.
 func (s *Stratum) Auth() error {
-	msg := StratumMsg{
-		Method: "mining.authorize",
-		ID:     s.ID,
-		Params: []string{s.User, s.Pass},
-	}
-	// Auth reply has no method so need a way to identify it.
-	// Ugly, but not much choise.
-	id, ok := msg.ID.(uint64)
-	if !ok {
-		return errJsonType
-	}
-	s.authID = id
-	s.ID += 1
-	poolLog.Tracef("> %v", msg)
-	m, err := json.Marshal(msg)
+	params := []string{s.User, s.Pass}
+	reply, err := s.callWait("mining.authorize", params,
+		stratumRequestTimeout)
 	if err != nil {
-		return err
+		return fmt.Errorf("Auth failure: %v", err)
 	}
-	_, err = s.Conn.Write(m)
-	if err != nil {
-		return err
-	}
-	_, err = s.Conn.Write([]byte("\n"))
-	if err != nil {
-		return err
+	aResp := reply.(*BasicReply)
+	if !aResp.Result {
+		return fmt.Errorf("Auth failure: %v", aResp.Error.ErrStr)
 	}
+	poolLog.Info("Logged in")
 	return nil
 }
 
-// Subscribe sends the subscribe message to get mining info for a worker.
+// Subscribe sends the subscribe message to get mining info for a worker and
+// waits for the reply.
 func (s *Stratum) Subscribe() error {
-	msg := StratumMsg{
-		Method: "mining.subscribe",
-		ID:     s.ID,
-		Params: []string{"decred-gominer/" + version()},
-	}
-	s.subID = msg.ID.(uint64)
-	s.ID++
-	m, err := json.Marshal(msg)
-	if err != nil {
-		return err
-	}
-	poolLog.Tracef("> %v", string(m))
-	_, err = s.Conn.Write(m)
-	if err != nil {
-		return err
-	}
-	_, err = s.Conn.Write([]byte("\n"))
+	params := []string{"decred-gominer/" + version()}
+	reply, err := s.callWait("mining.subscribe", params,
+		stratumRequestTimeout)
 	if err != nil {
-		return err
+		return fmt.Errorf("Subscribe failed: %v", err)
 	}
+	nResp := reply.(*SubscribeReply)
+	s.workMtx.Lock()
+	s.PoolWork.ExtraNonce1 = nResp.ExtraNonce1
+	s.PoolWork.ExtraNonce2Length = nResp.ExtraNonce2Length
+	s.workMtx.Unlock()
+	poolLog.Info("Subscribe reply received.")
+	poolLog.Trace(spew.Sdump(nResp))
 	return nil
 }
 
+// SubmitShare sends a share built by PrepSubmit to the pool.  Every share
+// gets its own reply, which is logged when it comes in.
+func (s *Stratum) SubmitShare(sub Submit) error {
+	job, nonce := sub.Params[1], sub.Params[4]
+	_, err := s.call(sub.Method, sub.Params, stratumRequestTimeout,
+		func(reply interface{}, err error) {
+			if err != nil {
+				poolLog.Errorf("Share for job %v nonce %v failed: %v",
+					job, nonce, err)
+				return
+			}
+			aResp := reply.(*BasicReply)
+			s.sharesMtx.Lock()
+			if aResp.Result {
+				s.accepted++
+			} else {
+				s.rejected++
+			}
+			s.sharesMtx.Unlock()
+			if aResp.Result {
+				poolLog.Infof("Share Accepted (job %v nonce %v)",
+					job, nonce)
+			} else {
+				poolLog.Errorf("Share rejected (job %v nonce %v): %v",
+					job, nonce, aResp.Error.ErrStr)
+			}
+		})
+	return err
+}
+
+// Shares returns the number of shares the pool accepted and rejected.
+func (s *Stratum) Shares() (accepted, rejected int) {
+	s.sharesMtx.Lock()
+	defer s.sharesMtx.Unlock()
+	return s.accepted, s.rejected
+}
+
 // Unmarshal provides a json umnarshaler for the commands.
 // I'm sure a lot of this can be generalized but the json we deal with
 // is pretty yucky.
@@ -405,157 +472,21 @@ func (s *Stratum) Unmarshal(blob []byte) (interface{}, error) {
 		return nil, err
 	}
 	poolLog.Trace("Received: method: ", method, " id: ", id)
-	if id == s.authID {
-		var (
-			objmap      map[string]json.RawMessage
-			id          uint64
-			result      bool
-			errorHolder []interface{}
-		)
-		err := json.Unmarshal(blob, &objmap)
-		if err != nil {
-			return nil, err
-		}
-		resp := &BasicReply{}
-
-		err = json.Unmarshal(objmap["id"], &id)
-		if err != nil {
-			return nil, err
-		}
-		resp.ID = id
-
-		err = json.Unmarshal(objmap["result"], &result)
-		if err != nil {
-			return nil, err
-		}
-		err = json.Unmarshal(objmap["error"], &errorHolder)
-		if err != nil {
-			return nil, err
-		}
-		resp.Result = result
-
-		if errorHolder != nil {
-			errN, ok := errorHolder[0].(float64)
-			if !ok {
-				return nil, errJsonType
-			}
-			errS, ok := errorHolder[1].(string)
-			if !ok {
-				return nil, errJsonType
-			}
-			resp.Error.ErrNum = uint64(errN)
-			resp.Error.ErrStr = errS
-		}
-
-		return resp, nil
-
-	}
-	if id == s.subID {
-		var resi []interface{}
-		err := json.Unmarshal(objmap["result"], &resi)
-		if err != nil {
-			return nil, err
-		}
-		poolLog.Trace(resi)
-		resp := &SubscribeReply{}
-
-		var objmap2 map[string]json.RawMessage
-		err = json.Unmarshal(blob, &objmap2)
-		if err != nil {
-			return nil, err
-		}
-
-		var resJS []json.RawMessage
-		err = json.Unmarshal(objmap["result"], &resJS)
-		if err != nil {
-			return nil, err
-		}
-
-		var msgPeak []interface{}
-		err = json.Unmarshal(resJS[0], &msgPeak)
-		if err != nil {
-			return nil, err
-		}
-
-		// The pools do not all agree on what this message looks like
-		// so we need to actually look at it before unmarshalling for
-		// real so we can use the right form.  Yuck.
-		if msgPeak[0] == "mining.notify" {
-			var innerMsg []string
-			err = json.Unmarshal(resJS[0], &innerMsg)
-			if err != nil {
-				return nil, err
+	// Replies have no method and are told apart by the method of the
+	// request with their id.
+	if method == "" {
+		if reqMethod, ok := s.pendingMethod(id); ok {
+			var resp interface{}
+			if reqMethod == "mining.subscribe" {
+				resp, err = unmarshalSubscribeReply(objmap, id)
+			} else {
+				resp, err = unmarshalBasicReply(objmap, id)
 			}
-			resp.SubscribeID = innerMsg[1]
-		} else {
-			var innerMsg [][]string
-			err = json.Unmarshal(resJS[0], &innerMsg)
 			if err != nil {
-				return nil, err
-			}
-			for i := 0; i < len(innerMsg); i++ {
-				if innerMsg[i][0] == "mining.notify" {
-					resp.SubscribeID = innerMsg[i][1]
-				}
-				if innerMsg[i][0] == "mining.set_difficulty" {
-					// Not all pools correctly put something
-					// in here so we will ignore it (we
-					// already have the default value of 1
-					// anyway and pool can send a new one.
-					// dcr.coinmine.pl puts something that
-					// is not a difficulty here which is why
-					// we ignore.
-				}
-			}
-
-		}
-		resp.ExtraNonce1 = resi[1].(string)
-		resp.ExtraNonce2Length = resi[2].(float64)
-		return resp, nil
-	}
-	if id == s.submitID && s.submitted {
-		var (
-			objmap      map[string]json.RawMessage
-			id          uint64
-			result      bool
-			errorHolder []interface{}
-		)
-		err := json.Unmarshal(blob, &objmap)
-		if err != nil {
-			return nil, err
-		}
-		resp := &BasicReply{}
-
-		err = json.Unmarshal(objmap["id"], &id)
-		if err != nil {
-			return nil, err
-		}
-		resp.ID = id
-
-		err = json.Unmarshal(objmap["result"], &result)
-		if err != nil {
-			return nil, err
-		}
-		err = json.Unmarshal(objmap["error"], &errorHolder)
-		if err != nil {
-			return nil, err
-		}
-		resp.Result = result
-
-		if errorHolder != nil {
-			errN, ok := errorHolder[0].(float64)
-			if !ok {
-				return nil, errJsonType
-			}
-			errS, ok := errorHolder[1].(string)
-			if !ok {
-				return nil, errJsonType
+				return nil, &replyError{id: id, err: err}
 			}
-			resp.Error.ErrNum = uint64(errN)
-			resp.Error.ErrStr = errS
+			return resp, nil
 		}
-
-		return resp, nil
 	}
 	switch method {
 	case "mining.notify":
@@ -622,8 +553,10 @@ func (s *Stratum) Unmarshal(blob []byte) (interface{}, error) {
 		if !ok {
 			return nil, errJsonType
 		}
+		s.workMtx.Lock()
 		s.Target = s.diffToTarget(difficulty)
 		s.Diff = difficulty
+		s.workMtx.Unlock()
 		var nres = StratumMsg{}
 		nres.Method = method
 		diffStr := strconv.FormatFloat(difficulty, 'E', -1, 32)
@@ -706,7 +639,125 @@ func (s *Stratum) Unmarshal(blob []byte) (interface{}, error) {
 	}
 }
 
-// PrepWork converts the stratum notify to getwork style data for mining.
+// unmarshalBasicReply unmarshals the reply to a request with a boolean
+// result.
+func unmarshalBasicReply(objmap map[string]json.RawMessage, id uint64) (*BasicReply, error) {
+	var (
+		result      bool
+		errorHolder []interface{}
+	)
+	resp := &BasicReply{ID: id}
+
+	err := json.Unmarshal(objmap["result"], &result)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(objmap["error"], &errorHolder)
+	if err != nil {
+		return nil, err
+	}
+	resp.Result = result
+
+	if errorHolder != nil {
+		if len(errorHolder) < 2 {
+			return nil, errJsonType
+		}
+		errN, ok := errorHolder[0].(float64)
+		if !ok {
+			return nil, errJsonType
+		}
+		errS, ok := errorHolder[1].(string)
+		if !ok {
+			return nil, errJsonType
+		}
+		resp.Error.ErrNum = uint64(errN)
+		resp.Error.ErrStr = errS
+	}
+
+	return resp, nil
+}
+
+// unmarshalSubscribeReply unmarshals the reply to mining.subscribe.
+func unmarshalSubscribeReply(objmap map[string]json.RawMessage, id uint64) (*SubscribeReply, error) {
+	var resi []interface{}
+	err := json.Unmarshal(objmap["result"], &resi)
+	if err != nil {
+		return nil, err
+	}
+	poolLog.Trace(resi)
+	if len(resi) < 3 {
+		return nil, errJsonType
+	}
+	resp := &SubscribeReply{ID: id}
+
+	var resJS []json.RawMessage
+	err = json.Unmarshal(objmap["result"], &resJS)
+	if err != nil {
+		return nil, err
+	}
+
+	var msgPeak []interface{}
+	err = json.Unmarshal(resJS[0], &msgPeak)
+	if err != nil {
+		return nil, err
+	}
+	if len(msgPeak) == 0 {
+		return nil, errJsonType
+	}
+
+	// The pools do not all agree on what this message looks like
+	// so we need to actually look at it before unmarshalling for
+	// real so we can use the right form.  Yuck.
+	if msgPeak[0] == "mining.notify" {
+		var innerMsg []string
+		err = json.Unmarshal(resJS[0], &innerMsg)
+		if err != nil {
+			return nil, err
+		}
+		if len(innerMsg) < 2 {
+			return nil, errJsonType
+		}
+		resp.SubscribeID = innerMsg[1]
+	} else {
+		var innerMsg [][]string
+		err = json.Unmarshal(resJS[0], &innerMsg)
+		if err != nil {
+			return nil, err
+		}
+		for i := 0; i < len(innerMsg); i++ {
+			if len(innerMsg[i]) < 2 {
+				continue
+			}
+			if innerMsg[i][0] == "mining.notify" {
+				resp.SubscribeID = innerMsg[i][1]
+			}
+			if innerMsg[i][0] == "mining.set_difficulty" {
+				// Not all pools correctly put something
+				// in here so we will ignore it (we
+				// already have the default value of 1
+				// anyway and pool can send a new one.
+				// dcr.coinmine.pl puts something that
+				// is not a difficulty here which is why
+				// we ignore.
+			}
+		}
+	}
+
+	extraNonce1, ok := resi[1].(string)
+	if !ok {
+		return nil, errJsonType
+	}
+	extraNonce2Length, ok := resi[2].(float64)
+	if !ok {
+		return nil, errJsonType
+	}
+	resp.ExtraNonce1 = extraNonce1
+	resp.ExtraNonce2Length = extraNonce2Length
+	return resp, nil
+}
+
+// PrepWork converts the stratum notify to getwork style data for mining.  It
+// must be called with workMtx held.
 func (s *Stratum) PrepWork() error {
 
 	// Build final extranonce
@@ -833,6 +884,7 @@ func (s *Stratum) PrepWork() error {
 
 	workPosition += 108
 	copy(workdata[workPosition:], extraNonce)
+	extraNonce2Start := workPosition + len(en1)
 	poolLog.Debugf("extranonce: %v", hex.EncodeToString(extraNonce))
 	poolLog.Tracef("partial workdata (extranonce): %v", hex.EncodeToString(workdata[:]))
 
@@ -849,15 +901,24 @@ func (s *Stratum) PrepWork() error {
 
 	var w Work
 	copy(w.Data[:], workdata[:])
-	copy(w.Target[:], target)
+	// Work targets are little endian, like those from getwork.
+	copy(w.Target[:], reverse(target))
+	w.Algorithm = selectPowAlgorithm(w.Data[:])
+	w.Pool = s
+	w.PoolJob = stratumJob{
+		ID:               s.PoolWork.JobID,
+		Ntime:            s.PoolWork.Ntime,
+		ExtraNonce2Start: extraNonce2Start,
+		ExtraNonce2End:   extraNonce2Start + len(en2),
+	}
 	poolLog.Tracef("final data %v, target %v", hex.EncodeToString(data), hex.EncodeToString(target))
 	s.PoolWork.Work = &w
 	return nil
 
 }
 
-// PrepSubmit formats a mining.sumbit message from the solved work.
-func (s *Stratum) PrepSubmit(data []byte) (Submit, error) {
+// PrepSubmit formats a mining.sumbit message from the solved work of the job.
+func (s *Stratum) PrepSubmit(data []byte, job stratumJob) (Submit, error) {
 	sub := Submit{}
 	sub.Method = "mining.submit"
 
@@ -878,40 +939,22 @@ func (s *Stratum) PrepSubmit(data []byte) (Submit, error) {
 		return sub, err
 	}
 
-	//en2 := strconv.FormatUint(s.PoolWork.ExtraNonce2, 16)
 	nonce := strconv.FormatUint(uint64(submittedHeader.Nonce), 16)
 	time := encodeTime(submittedHeader.Timestamp)
 
-	en1, err := hex.DecodeString(s.PoolWork.ExtraNonce1)
-	if err != nil {
-		poolLog.Error("Error decoding ExtraNonce1.")
-		//return err
-	}
-	poolLog.Tracef("en1 %v s.PoolWork.ExtraNonce1 %v", en1, s.PoolWork.ExtraNonce1)
-	// Work out padding
-	tmp := []string{"%0", strconv.Itoa(int(s.PoolWork.ExtraNonce2Length) * 2), "x"}
-	fmtString := strings.Join(tmp, "")
-	en2, err := hex.DecodeString(fmt.Sprintf(fmtString, s.PoolWork.ExtraNonce2))
-	if err != nil {
-		poolLog.Error("Error decoding ExtraNonce2.")
-		//return err
+	// The extranonce2 is taken from the header, where devices put their
+	// own in place of the one the work was built with.
+	if job.ExtraNonce2End > wire.MaxBlockHeaderPayload {
+		return sub, fmt.Errorf("Extranonce2 of job %v does not fit in "+
+			"the header", job.ID)
 	}
-	poolLog.Tracef("en2 %v s.PoolWork.ExtraNonce2 %v", en2, s.PoolWork.ExtraNonce2)
-	extraNonce := append(en1[:], en2[:]...)
-	poolLog.Tracef("extraNonce %v", extraNonce)
-
-	s.ID++
-	sub.ID = s.ID
-	s.submitID = s.ID
-	s.submitted = true
-
-	poolLog.Tracef("ntime %v", s.PoolWork.Ntime)
+	en2 := decodedData[job.ExtraNonce2Start:job.ExtraNonce2End]
 
-	poolLog.Tracef("raw User %v JobId %v xnonce2 %v xnonce2length %v time %v nonce %v", s.User, s.PoolWork.JobID, s.PoolWork.ExtraNonce2, s.PoolWork.ExtraNonce2Length, submittedHeader.Timestamp, submittedHeader.Nonce)
+	poolLog.Tracef("raw User %v JobId %v xnonce2 %v time %v nonce %v", s.User, job.ID, en2, submittedHeader.Timestamp, submittedHeader.Nonce)
 
-	poolLog.Tracef("encoded User %v JobId %v xnonce2 %v time %v nonce %v", s.User, s.PoolWork.JobID, en2, string(time), nonce)
+	poolLog.Tracef("encoded User %v JobId %v xnonce2 %v time %v nonce %v", s.User, job.ID, hex.EncodeToString(en2), string(time), nonce)
 
-	sub.Params = []string{s.User, s.PoolWork.JobID, hex.EncodeToString(en2), s.PoolWork.Ntime, nonce}
+	sub.Params = []string{s.User, job.ID, hex.EncodeToString(en2), job.Ntime, nonce}
 	// pool->user, work->job_id + 8, xnonce2str, ntimestr, noncestr, nvotestr
 
 	return sub, nil
@@ -948,44 +991,32 @@ func reverseToInt(s string) (int32, error) {
 	return int32(i), err
 }
 
+// diffToTarget returns the share target of a pool difficulty as big endian
+// hex.  Difficulty 1 is the target 0x00000000ffff0000..., and difficulties
+// below 1, which pools hand to very slow miners, give targets up to the
+// largest 256 bit number.
 func (s *Stratum) diffToTarget(diff float64) string {
-	// diff/0 would be bad.
-	if s.Diff == 0 {
-		s.Diff = 1
-	}
-	// Also if diff wasn't set properly go with default
-	// rather then end if div by 0.
-	if diff == 0 {
+	// If diff wasn't set properly go with default rather than divide
+	// by 0.
+	if diff <= 0 {
 		diff = 1
 	}
-	diffNew := int64(diff / s.Diff)
-	_, targetHex := s.getTargetHex(diffNew)
-	return targetHex
-}
 
-// Adapted from https://github.com/sammy007/go-cryptonote-pool.git
-func (s *Stratum) getTargetHex(diff int64) (uint32, string) {
-	var Diff1 *big.Int
-	Diff1 = new(big.Int)
-	Diff1.SetString("00000000FFFF0000000000000000000000000000000000000000000000000000", 16)
+	diff1 := new(big.Int)
+	diff1.SetString("00000000FFFF0000000000000000000000000000000000000000000000000000", 16)
+	quo := new(big.Float).Quo(new(big.Float).SetInt(diff1),
+		big.NewFloat(diff))
+	target, _ := quo.Int(nil)
+	maxTarget := new(big.Int).Lsh(big.NewInt(1), 256)
+	maxTarget.Sub(maxTarget, big.NewInt(1))
+	if target.Cmp(maxTarget) > 0 {
+		target = maxTarget
+	}
 
 	padded := make([]byte, 32)
-
-	diff2 := new(big.Int)
-	diff2.SetInt64(int64(diff))
-
-	diff3 := new(big.Int)
-	diff3 = diff3.Div(Diff1, diff2)
-
-	diffBuff := diff3.Bytes()
-	copy(padded[32-len(diffBuff):], diffBuff)
-	buff := padded[0:32]
-	var target uint32
-	targetBuff := bytes.NewReader(buff)
-	binary.Read(targetBuff, binary.LittleEndian, &target)
-	targetHex := hex.EncodeToString(buff)
-
-	return target, targetHex
+	targetBuff := target.Bytes()
+	copy(padded[32-len(targetBuff):], targetBuff)
+	return hex.EncodeToString(padded)
 }
 
 func reverse(src []byte) []byte {
@@ -0,0 +1,167 @@
+// Copyright (c) 2026 The Decred developers
+
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestDiffToTarget checks the share targets of pool difficulties, including
+// difficulties below 1 and ones that are not set.
+func TestDiffToTarget(t *testing.T) {
+	tests := []struct {
+		diff float64
+		want string
+	}{
+		{1, "00000000ffff" + strings.Repeat("0", 52)},
+		{0, "00000000ffff" + strings.Repeat("0", 52)},
+		{2, "000000007fff8" + strings.Repeat("0", 51)},
+		{0.5, "00000001fffe" + strings.Repeat("0", 52)},
+		{1.0 / (1 << 24), "00ffff" + strings.Repeat("0", 58)},
+		{1e-10, strings.Repeat("f", 64)},
+		{8, "000000001fffe" + strings.Repeat("0", 51)},
+		{512, "00000000007fff8" + strings.Repeat("0", 49)},
+	}
+	var s Stratum
+	for _, test := range tests {
+		if got := s.diffToTarget(test.diff); got != test.want {
+			t.Errorf("Target of difficulty %v is %s, want %s",
+				test.diff, got, test.want)
+		}
+	}
+}
+
+// TestPoolWorkTarget checks the targets of the work built from the jobs of
+// pools at real share difficulties, including one the pool sets twice, against
+// their known values.  Work targets are little endian.
+func TestPoolWorkTarget(t *testing.T) {
+	tests := []struct {
+		diff float64
+		want string
+	}{
+		{1, "00000000ffff" + strings.Repeat("0", 52)},
+		{8, "000000001fffe" + strings.Repeat("0", 51)},
+		{512, "00000000007fff8" + strings.Repeat("0", 49)},
+	}
+	for _, test := range tests {
+		pool := newMockPool(t, test.diff)
+		s, err := StratumConn(pool.url(), "user", "pass")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer s.Close()
+		var work *Work
+		waitFor(t, "work", func() bool {
+			work, err = GetPoolWork(s)
+			return err == nil
+		})
+		got := hex.EncodeToString(reverse(work.Target[:]))
+		if got != test.want {
+			t.Errorf("Work target at difficulty %v is %s, want %s",
+				test.diff, got, test.want)
+		}
+
+		// A pool setting the difficulty it already set keeps the
+		// target.
+		msg := fmt.Sprintf(`{"id":null,"method":"mining.set_difficulty",`+
+			`"params":[%v]}`, test.diff)
+		if _, err := s.Unmarshal([]byte(msg)); err != nil {
+			t.Fatal(err)
+		}
+		s.workMtx.Lock()
+		target := s.Target
+		s.workMtx.Unlock()
+		if target != test.want {
+			t.Errorf("Target at difficulty %v set twice is %s, "+
+				"want %s", test.diff, target, test.want)
+		}
+	}
+}
+
+// TestPrepSubmitExtraNonce2 checks that the share of a solution carries the
+// extranonce2 in its header, which Device.SetWork sets to the index of the
+// device, rather than the one the work was built with.
+func TestPrepSubmitExtraNonce2(t *testing.T) {
+	pool := newMockPool(t, 1e-10)
+	s, err := StratumConn(pool.url(), "user", "pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	var work *Work
+	waitFor(t, "work", func() bool {
+		work, err = GetPoolWork(s)
+		return err == nil
+	})
+
+	cfg = &config{Algo: autoPowAlgorithm, SimBits: 32}
+	const index = 3
+	w := newSimWorker(index, 1)
+	solved := make(chan *Work, 10)
+	d := NewDevice(index, w, solved)
+	done := make(chan struct{})
+	go func() {
+		d.Run()
+		close(done)
+	}()
+	defer func() {
+		d.Stop()
+		<-done
+	}()
+	w.InjectSolution(1)
+	d.SetWork(work)
+	var solution *Work
+	select {
+	case solution = <-solved:
+	case <-time.After(10 * time.Second):
+		t.Fatal("No solution")
+	}
+
+	sub, err := s.PrepSubmit(solution.Data[:], solution.PoolJob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	job := solution.PoolJob
+	header := solution.Data[job.ExtraNonce2Start:job.ExtraNonce2End]
+	var want [4]byte
+	solution.Algorithm.ByteOrder().PutUint32(want[:], index)
+	got := sub.Params[2]
+	if got != hex.EncodeToString(header) ||
+		got != hex.EncodeToString(want[:]) {
+		t.Fatalf("Submitted extranonce2 %s, header has %x, want "+
+			"device index %x", got, header, want)
+	}
+}
+
+// TestStratumReconnectRequest checks that the miner moves to the pool a
+// client.reconnect request names after the requested wait.
+func TestStratumReconnectRequest(t *testing.T) {
+	from, to := newMockPool(t, 1), newMockPool(t, 1)
+	s, err := StratumConn(from.url(), "user", "pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	host, port, err := net.SplitHostPort(to.ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	requested := time.Now()
+	from.send(fmt.Sprintf(`{"id":null,"method":"client.reconnect",`+
+		`"params":["%s",%s,1]}`, host, port))
+	waitFor(t, "reconnect", func() bool {
+		return to.connections() > 0 && s.Connected()
+	})
+	if time.Since(requested) < time.Second {
+		t.Fatal("Reconnected before the requested wait")
+	}
+	if n := from.connections(); n != 1 {
+		t.Fatalf("Connected to the old pool %d times", n)
+	}
+}
@@ -0,0 +1,227 @@
+// Copyright (c) 2026 The Decred developers
+
+package main
+
+import (
+	"bufio"
+	"crypto/tls"
+	"math/rand"
+	"net"
+	"time"
+)
+
+const (
+	// stratumDialTimeout is how long connecting to the pool may take.
+	stratumDialTimeout = 30 * time.Second
+
+	// stratumKeepAlive is the TCP keep-alive period of pool connections,
+	// which lets the system notice pools that went away without closing
+	// the connection.
+	stratumKeepAlive = 30 * time.Second
+
+	// stratumIdleTimeout is how long the pool may go without sending
+	// anything before the connection is taken to be lost.  Pools send new
+	// work at least for every block.
+	stratumIdleTimeout = 10 * time.Minute
+
+	// stratumMinBackoff and stratumMaxBackoff bound the delay between
+	// reconnect attempts, which doubles with every failed attempt.
+	stratumMinBackoff = time.Second
+	stratumMaxBackoff = 2 * time.Minute
+)
+
+// stratumBackoff returns the delay before reconnect attempt n, counting from
+// zero.  Up to half of the delay is taken off at random so that the miners
+// that lost the same pool do not all come back at once.
+func stratumBackoff(n int) time.Duration {
+	d := stratumMaxBackoff
+	if n < 16 && stratumMinBackoff<<uint(n) < d {
+		d = stratumMinBackoff << uint(n)
+	}
+	return d - time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// dial connects to the pool, over TLS when it has TLS settings.
+func (s *Stratum) dial() (net.Conn, error) {
+	dialer := net.Dialer{
+		Timeout:   stratumDialTimeout,
+		KeepAlive: stratumKeepAlive,
+	}
+	if s.tlsConfig != nil {
+		return tls.DialWithDialer(&dialer, "tcp", s.Pool, s.tlsConfig)
+	}
+	return dialer.Dial("tcp", s.Pool)
+}
+
+// poolHost returns the host name of a pool address, which is what its TLS
+// certificate is issued for.
+func poolHost(pool string) string {
+	host, _, err := net.SplitHostPort(pool)
+	if err != nil {
+		return pool
+	}
+	return host
+}
+
+// connect connects to the pool, subscribes and authorizes the worker, and
+// then keeps the connection up in the background until Close is called.
+func (s *Stratum) connect() error {
+	conn, err := s.dial()
+	if err != nil {
+		return err
+	}
+	lost, err := s.startSession(conn)
+	if err != nil {
+		return err
+	}
+	go s.supervise(lost)
+	return nil
+}
+
+// probe keeps trying to connect to the pool in the background, backing off
+// after every failed attempt, and then keeps the connection up like connect
+// until Close is called.
+func (s *Stratum) probe() {
+	lost := make(chan struct{})
+	close(lost)
+	go s.supervise(lost)
+}
+
+// startSession makes conn the connection to the pool, starts listening on it
+// and subscribes and authorizes the worker.  The returned channel is closed
+// when the connection is lost.  The work of the previous session is dropped,
+// so there is no work until the pool sends some on this one.
+func (s *Stratum) startSession(conn net.Conn) (<-chan struct{}, error) {
+	s.workMtx.Lock()
+	s.PoolWork = NotifyWork{}
+	s.workMtx.Unlock()
+
+	s.connMtx.Lock()
+	s.Conn = conn
+	s.Reader = bufio.NewReader(conn)
+	s.sessionStart = time.Now()
+	s.lastNotify = time.Time{}
+	s.connMtx.Unlock()
+
+	lost := make(chan struct{})
+	go func() {
+		s.Listen()
+		// No replies come in any more.
+		s.failPending(errStratumDisconnected)
+		close(lost)
+	}()
+
+	err := s.Subscribe()
+	if err == nil {
+		err = s.Auth()
+	}
+	if err != nil {
+		s.disconnect()
+		<-lost
+		return nil, err
+	}
+
+	s.connMtx.Lock()
+	s.ready = true
+	s.connMtx.Unlock()
+	return lost, nil
+}
+
+// supervise keeps the connection to the pool up until Close is called.  lost
+// is closed when the current connection is lost, at which point supervise
+// reconnects, backing off after every failed attempt.
+func (s *Stratum) supervise(lost <-chan struct{}) {
+	for {
+		select {
+		case <-lost:
+		case <-s.quit:
+			s.disconnect()
+			<-lost
+			return
+		}
+		s.disconnect()
+
+		// A pool asking to be reconnected to says how long to wait.
+		wait := s.reconnectWait
+		s.reconnectWait = 0
+
+		for n := 0; ; n++ {
+			delay := stratumBackoff(n)
+			if n == 0 && wait > 0 {
+				delay = wait
+			}
+			poolLog.Infof("Reconnecting to %v in %v", s.Pool,
+				delay.Truncate(time.Millisecond))
+			select {
+			case <-s.quit:
+				return
+			case <-time.After(delay):
+			}
+
+			conn, err := s.dial()
+			if err != nil {
+				poolLog.Errorf("Reconnect failed: %v", err)
+				continue
+			}
+			lost, err = s.startSession(conn)
+			if err != nil {
+				poolLog.Errorf("Reconnect failed: %v", err)
+				continue
+			}
+			poolLog.Infof("Reconnected to %v", s.Pool)
+			break
+		}
+	}
+}
+
+// disconnect closes the connection to the pool and fails the requests that
+// were waiting for a reply on it.
+func (s *Stratum) disconnect() {
+	s.connMtx.Lock()
+	if s.Conn != nil {
+		s.Conn.Close()
+		s.Conn = nil
+	}
+	s.ready = false
+	s.connMtx.Unlock()
+
+	s.failPending(errStratumDisconnected)
+}
+
+// dropConn closes the connection to the pool so that the supervisor
+// reconnects.
+func (s *Stratum) dropConn() {
+	s.connMtx.Lock()
+	if s.Conn != nil {
+		s.Conn.Close()
+	}
+	s.connMtx.Unlock()
+}
+
+// Connected returns whether the worker is subscribed and authorized with the
+// pool, which is when there may be work from it.
+func (s *Stratum) Connected() bool {
+	s.connMtx.Lock()
+	defer s.connMtx.Unlock()
+	return s.ready
+}
+
+// WorkAge returns how long ago the pool last sent work, or how long ago the
+// current connection was set up when it has not sent any yet, and whether it
+// has sent any.
+func (s *Stratum) WorkAge() (time.Duration, bool) {
+	s.connMtx.Lock()
+	defer s.connMtx.Unlock()
+	if s.lastNotify.IsZero() {
+		return time.Since(s.sessionStart), false
+	}
+	return time.Since(s.lastNotify), true
+}
+
+// Close disconnects from the pool for good.
+func (s *Stratum) Close() {
+	s.closeOnce.Do(func() {
+		close(s.quit)
+		s.dropConn()
+	})
+}
@@ -0,0 +1,179 @@
+// Copyright (c) 2026 The Decred developers
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// stratumRequestTimeout is how long the pool is given to reply to a request
+// before the request fails.
+const stratumRequestTimeout = 30 * time.Second
+
+var (
+	// errStratumTimeout ends requests the pool did not reply to in time.
+	errStratumTimeout = errors.New("Stratum request timed out")
+
+	// errStratumDisconnected ends requests still waiting for their reply
+	// when the connection to the pool is lost.
+	errStratumDisconnected = errors.New("Stratum connection lost")
+)
+
+// stratumCallback is called exactly once with the reply to a request, or with
+// the error that ended it.  The reply is a *SubscribeReply for
+// mining.subscribe and a *BasicReply for every other method.  Callbacks run
+// on the listener or on a timer goroutine, so they must not block.
+type stratumCallback func(reply interface{}, err error)
+
+// stratumRequest is a request waiting for its reply.
+type stratumRequest struct {
+	method   string
+	callback stratumCallback
+	timer    *time.Timer
+}
+
+// replyError is returned by Unmarshal for a reply to a pending request that
+// could not be parsed, so that the request can be failed right away instead
+// of timing out.
+type replyError struct {
+	id  uint64
+	err error
+}
+
+// Error returns the error as a string.
+func (e *replyError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the parse error.
+func (e *replyError) Unwrap() error {
+	return e.err
+}
+
+// call sends a request to the pool and registers callback to be called with
+// its reply, or with errStratumTimeout when the pool does not reply within
+// timeout.  Every request gets a new id, so any number of requests may be
+// waiting at once.  The callback is not called when the request cannot be
+// sent.
+func (s *Stratum) call(method string, params []string, timeout time.Duration, callback stratumCallback) (uint64, error) {
+	s.pendingMtx.Lock()
+	s.ID++
+	id := s.ID
+	if s.pending == nil {
+		s.pending = make(map[uint64]*stratumRequest)
+	}
+	s.pending[id] = &stratumRequest{method: method, callback: callback}
+	s.pendingMtx.Unlock()
+
+	err := s.send(StratumMsg{Method: method, Params: params, ID: id})
+	if err != nil {
+		s.pendingMtx.Lock()
+		delete(s.pending, id)
+		s.pendingMtx.Unlock()
+		return 0, err
+	}
+
+	// The timer only starts once the request is out, and not at all when
+	// the reply already came in.
+	s.pendingMtx.Lock()
+	if req, ok := s.pending[id]; ok {
+		req.timer = time.AfterFunc(timeout, func() {
+			// A pool that stops replying is most likely gone
+			// without the connection having been closed.
+			if s.complete(id, nil, errStratumTimeout) {
+				s.dropConn()
+			}
+		})
+	}
+	s.pendingMtx.Unlock()
+
+	return id, nil
+}
+
+// callWait is call for callers that wait for the reply.
+func (s *Stratum) callWait(method string, params []string, timeout time.Duration) (interface{}, error) {
+	type result struct {
+		reply interface{}
+		err   error
+	}
+	c := make(chan result, 1)
+	_, err := s.call(method, params, timeout,
+		func(reply interface{}, err error) {
+			c <- result{reply, err}
+		})
+	if err != nil {
+		return nil, err
+	}
+	r := <-c
+	return r.reply, r.err
+}
+
+// send writes a message to the pool as a single line of JSON.
+func (s *Stratum) send(msg interface{}) error {
+	m, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	poolLog.Tracef("> %s", m)
+
+	s.connMtx.Lock()
+	defer s.connMtx.Unlock()
+	if s.Conn == nil {
+		return errStratumDisconnected
+	}
+	err = s.Conn.SetWriteDeadline(time.Now().Add(stratumRequestTimeout))
+	if err != nil {
+		return err
+	}
+	_, err = s.Conn.Write(append(m, '\n'))
+	return err
+}
+
+// pendingMethod returns the method of the pending request with the id.
+func (s *Stratum) pendingMethod(id uint64) (string, bool) {
+	s.pendingMtx.Lock()
+	defer s.pendingMtx.Unlock()
+
+	req, ok := s.pending[id]
+	if !ok {
+		return "", false
+	}
+	return req.method, true
+}
+
+// complete ends the pending request with the id and calls its callback with
+// the reply or error.  It returns false when there is no such request, which
+// happens when a reply comes in after its request timed out.
+func (s *Stratum) complete(id uint64, reply interface{}, err error) bool {
+	s.pendingMtx.Lock()
+	req, ok := s.pending[id]
+	if ok {
+		delete(s.pending, id)
+		if req.timer != nil {
+			req.timer.Stop()
+		}
+	}
+	s.pendingMtx.Unlock()
+
+	if !ok {
+		return false
+	}
+	req.callback(reply, err)
+	return true
+}
+
+// failPending ends every pending request with err.
+func (s *Stratum) failPending(err error) {
+	s.pendingMtx.Lock()
+	ids := make([]uint64, 0, len(s.pending))
+	for id := range s.pending {
+		ids = append(ids, id)
+	}
+	s.pendingMtx.Unlock()
+
+	for _, id := range ids {
+		s.complete(id, nil, err)
+	}
+}